@@ -0,0 +1,152 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// listCache is a saved --list result along with the fingerprint of the spec
+// files it was listed from.
+type listCache struct {
+	Fingerprint string         `json:"fingerprint"`
+	Data        PlaywrightJSON `json:"data"`
+}
+
+type cachedDataMsg struct {
+	data  PlaywrightJSON
+	fresh bool
+}
+
+// listCacheable reports whether a listing can be cached. --only-changed and
+// --last-failed depend on git and the previous run rather than the files.
+func listCacheable(opts listOptions) bool {
+	return !noCache && jsonDataPath == "" && !opts.onlyChanged && !opts.lastFailed
+}
+
+// listCachePath returns where the listing for opts is cached, keyed by the
+// working directory, config file, projects and grep.
+func listCachePath(opts listOptions) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	cwd, _ := os.Getwd()
+	config := configPath
+	if config != "" {
+		config, _ = filepath.Abs(config)
+	}
+	if len(workspaceConfigs) > 0 {
+		config = strings.Join(workspaceConfigs, ",")
+	}
+	projects := append([]string{}, opts.projects...)
+	sort.Strings(projects)
+
+	key := strings.Join([]string{cwd, config, strings.Join(projects, ","), opts.grep, opts.grepInvert}, "\x00")
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dir, "pwgo", "list-"+hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// listFingerprint hashes the size and modification time of every source file
+// under rootDir and of the Playwright config.
+func listFingerprint(rootDir string) string {
+	if rootDir == "" {
+		rootDir, _ = os.Getwd()
+	}
+	snapshot := watchSnapshot(rootDir, specSourceFile)
+
+	configs := []string{configPath}
+	switch {
+	case len(workspaceConfigs) > 0:
+		configs = workspaceConfigs
+	case configPath == "":
+		configs, _ = filepath.Glob("playwright.config.*")
+	}
+	for _, config := range configs {
+		if abs, err := filepath.Abs(config); err == nil {
+			if info, err := os.Stat(abs); err == nil {
+				snapshot[abs] = fmt.Sprintf("%d/%d", info.Size(), info.ModTime().UnixNano())
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(snapshot))
+	for path := range snapshot {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	h := sha256.New()
+	for _, path := range paths {
+		fmt.Fprintf(h, "%s %s\n", path, snapshot[path])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// readListCache loads the cached listing for opts. fresh is false when spec
+// files have changed since it was saved.
+func readListCache(opts listOptions) (data PlaywrightJSON, fresh, ok bool) {
+	if !listCacheable(opts) {
+		return data, false, false
+	}
+	path, err := listCachePath(opts)
+	if err != nil {
+		return data, false, false
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return data, false, false
+	}
+	var cache listCache
+	if err := json.Unmarshal(raw, &cache); err != nil || len(cache.Data.Suites) == 0 {
+		return data, false, false
+	}
+	return cache.Data, cache.Fingerprint == listFingerprint(cache.Data.Config.RootDir), true
+}
+
+func writeListCache(opts listOptions, data PlaywrightJSON) error {
+	if !listCacheable(opts) {
+		return nil
+	}
+	path, err := listCachePath(opts)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(listCache{Fingerprint: listFingerprint(data.Config.RootDir), Data: data})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, 0o644)
+}
+
+// initialLoad shows the cached listing when there is one and lists the tests
+// otherwise.
+func initialLoad() tea.Cmd {
+	return func() tea.Msg {
+		if data, fresh, ok := readListCache(currentListOptions); ok {
+			return cachedDataMsg{data: data, fresh: fresh}
+		}
+		data, err := loadData(currentListOptions)
+		return dataReloadedMsg{data: data, err: err}
+	}
+}
+
+// handleCachedData applies a cached listing, refreshing it in the background
+// when spec files have changed since it was saved.
+func (m model) handleCachedData(msg cachedDataMsg) (tea.Model, tea.Cmd) {
+	m.reloading = false
+	cmd := m.dataLoaded(msg.data)
+	if msg.fresh {
+		return m, cmd
+	}
+	return m, tea.Batch(cmd, m.startReload())
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withListCache points the cache at a temporary directory and clears the
+// globals that turn caching off.
+func withListCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	oldJSON, oldConfig, oldNoCache := jsonDataPath, configPath, noCache
+	t.Cleanup(func() { jsonDataPath, configPath, noCache = oldJSON, oldConfig, oldNoCache })
+	jsonDataPath, configPath, noCache = "", "", false
+}
+
+func TestListCache_RoundTripAndStaleness(t *testing.T) {
+	withListCache(t)
+	root := t.TempDir()
+	spec := filepath.Join(root, "cart.spec.ts")
+	os.WriteFile(spec, []byte("test('adds', () => {})\n"), 0o644)
+
+	data := presetTestData()
+	data.Config.RootDir = root
+	opts := listOptions{projects: []string{"chromium", "firefox"}, grep: "@smoke"}
+	if err := writeListCache(opts, data); err != nil {
+		t.Fatalf("writeListCache failed: %v", err)
+	}
+
+	// Project order does not change the key
+	got, fresh, ok := readListCache(listOptions{projects: []string{"firefox", "chromium"}, grep: "@smoke"})
+	if !ok || !fresh || len(got.Suites) != len(data.Suites) {
+		t.Fatalf("expected a fresh cache hit, got ok=%v fresh=%v", ok, fresh)
+	}
+	if _, _, ok := readListCache(listOptions{projects: opts.projects}); ok {
+		t.Errorf("expected a different grep to miss the cache")
+	}
+
+	os.WriteFile(spec, []byte("test('adds', () => {})\ntest('removes', () => {})\n"), 0o644)
+	if _, fresh, ok := readListCache(opts); !ok || fresh {
+		t.Errorf("expected an edited spec file to make the cache stale, got ok=%v fresh=%v", ok, fresh)
+	}
+}
+
+func TestListCacheable(t *testing.T) {
+	withListCache(t)
+	if !listCacheable(listOptions{grep: "@smoke"}) {
+		t.Errorf("expected a plain listing to be cacheable")
+	}
+	if listCacheable(listOptions{onlyChanged: true}) || listCacheable(listOptions{lastFailed: true}) {
+		t.Errorf("expected --only-changed and --last-failed listings not to be cached")
+	}
+	noCache = true
+	if listCacheable(listOptions{}) {
+		t.Errorf("expected --no-cache to disable the cache")
+	}
+}
+
+func TestHandleCachedData_RefreshesWhenStale(t *testing.T) {
+	m := NewLoadingModel(nil, nil)
+	updated, _ := m.Update(cachedDataMsg{data: presetTestData(), fresh: true})
+	m = updated.(model)
+	if m.loading || m.reloading || len(m.originalTests) != 2 {
+		t.Errorf("expected a fresh cache to be used without listing again")
+	}
+
+	m = NewLoadingModel(nil, nil)
+	updated, cmd := m.Update(cachedDataMsg{data: presetTestData()})
+	m = updated.(model)
+	if m.loading || !m.reloading || cmd == nil || len(m.originalTests) != 2 {
+		t.Errorf("expected a stale cache to be shown while listing again")
+	}
+}
@@ -0,0 +1,393 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var changedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+type diffBaseKind int
+
+const (
+	// diffWorkingTree compares with the index, so only unstaged changes count
+	diffWorkingTree diffBaseKind = iota
+	diffHead
+	diffMergeBase
+	diffRef
+)
+
+// diffBase is what the Changed list compares the working tree with.
+type diffBase struct {
+	kind diffBaseKind
+	ref  string
+}
+
+// lineRange is an inclusive range of lines in the new version of a file.
+type lineRange struct {
+	start, end int
+}
+
+type changesMsg struct {
+	base    diffBase
+	label   string
+	changes map[string][]lineRange
+	// sources are the contents of the changed script files, for finding
+	// where each test ends
+	sources map[string][]byte
+	err     error
+}
+
+// next cycles through HEAD, the merge-base with the main branch, the last
+// ref entered and the working tree.
+func (b diffBase) next(ref string) diffBase {
+	switch b.kind {
+	case diffWorkingTree:
+		return diffBase{kind: diffHead}
+	case diffHead:
+		return diffBase{kind: diffMergeBase}
+	case diffMergeBase:
+		if ref != "" {
+			return diffBase{kind: diffRef, ref: ref}
+		}
+	}
+	return diffBase{kind: diffWorkingTree}
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-c", "core.quotePath=false"}, args...)...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], detail)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// mainBranch returns main or, failing that, master.
+func mainBranch(dir string) (string, error) {
+	for _, branch := range []string{"main", "master"} {
+		if _, err := git(dir, "rev-parse", "--verify", "--quiet", branch); err == nil {
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("no main or master branch to compare with")
+}
+
+// gitChanges returns the lines changed since base in each file under the
+// repository containing dir, keyed by absolute path. Untracked files count
+// as changed throughout.
+func gitChanges(dir string, base diffBase) (string, map[string][]lineRange, error) {
+	top, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", nil, err
+	}
+	top = strings.TrimSpace(top)
+
+	args := []string{"diff", "--unified=0", "--no-color", "--no-ext-diff"}
+	label := "working tree"
+	switch base.kind {
+	case diffHead:
+		args = append(args, "HEAD")
+		label = "HEAD"
+	case diffMergeBase:
+		branch, err := mainBranch(top)
+		if err != nil {
+			return "", nil, err
+		}
+		mergeBase, err := git(top, "merge-base", "HEAD", branch)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, strings.TrimSpace(mergeBase))
+		label = "merge-base with " + branch
+	case diffRef:
+		args = append(args, base.ref, "--")
+		label = base.ref
+	}
+	out, err := git(top, args...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	changes := map[string][]lineRange{}
+	for file, ranges := range parseDiff(out) {
+		changes[filepath.Join(top, file)] = ranges
+	}
+	untracked, err := git(top, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return "", nil, err
+	}
+	for _, file := range strings.Split(strings.TrimSpace(untracked), "\n") {
+		if file != "" {
+			changes[filepath.Join(top, file)] = []lineRange{{1, math.MaxInt}}
+		}
+	}
+	return label, changes, nil
+}
+
+// parseDiff reads the changed line ranges of each file from a diff made with
+// --unified=0. Deletions count as a change to the line before them.
+func parseDiff(out string) map[string][]lineRange {
+	changes := map[string][]lineRange{}
+	var file string
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = strings.TrimRight(strings.TrimPrefix(line, "+++ "), "\t")
+			if file == "/dev/null" {
+				file = ""
+			}
+			file = strings.TrimPrefix(file, "b/")
+		case strings.HasPrefix(line, "@@ ") && file != "":
+			fields := strings.Fields(line)
+			if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+				continue
+			}
+			start, count := parseHunkRange(strings.TrimPrefix(fields[2], "+"))
+			if count == 0 {
+				changes[file] = append(changes[file], lineRange{max(start, 1), max(start, 1)})
+			} else {
+				changes[file] = append(changes[file], lineRange{start, start + count - 1})
+			}
+		}
+	}
+	return changes
+}
+
+func parseHunkRange(s string) (start, count int) {
+	count = 1
+	if i := strings.Index(s, ","); i >= 0 {
+		count, _ = strconv.Atoi(s[i+1:])
+		s = s[:i]
+	}
+	start, _ = strconv.Atoi(s)
+	return start, count
+}
+
+func (m model) loadChanges() tea.Cmd {
+	dir := m.rootDir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	base := m.changeBase
+	return func() tea.Msg {
+		label, changes, err := gitChanges(dir, base)
+		return changesMsg{base: base, label: label, changes: changes, sources: readSources(changes), err: err}
+	}
+}
+
+// readSources reads the changed files that can hold tests. Files that cannot
+// be read are left out.
+func readSources(changes map[string][]lineRange) map[string][]byte {
+	sources := map[string][]byte{}
+	for path := range changes {
+		if _, ok := watchExtensions[filepath.Ext(path)]; !ok {
+			continue
+		}
+		if src, err := os.ReadFile(path); err == nil {
+			sources[path] = src
+		}
+	}
+	return sources
+}
+
+// blockEnd returns the line on which the brackets opened from line start
+// onwards close again, which for a test is the end of its body. Strings and
+// comments are skipped. ok is false when the brackets never balance.
+func blockEnd(src []byte, start int) (end int, ok bool) {
+	line := 1
+	depth := 0
+	opened := false
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		if c == '\n' {
+			line++
+			continue
+		}
+		if line < start {
+			continue
+		}
+		switch {
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i+1 < len(src) && src[i+1] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			for i += 2; i+1 < len(src) && !(src[i] == '*' && src[i+1] == '/'); i++ {
+				if src[i] == '\n' {
+					line++
+				}
+			}
+			i++
+		case c == '\'' || c == '"' || c == '`':
+			for i++; i < len(src) && src[i] != c; i++ {
+				if src[i] == '\\' && i+1 < len(src) {
+					i++
+				} else if src[i] == '\n' && c != '`' {
+					// An unterminated quote ends with its line
+					i--
+					break
+				}
+				if src[i] == '\n' {
+					line++
+				}
+			}
+		case c == '(' || c == '{' || c == '[':
+			depth++
+			opened = true
+		case opened && (c == ')' || c == '}' || c == ']'):
+			depth--
+			if depth == 0 {
+				return line, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func newChangedList() list.Model {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Changed"
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keyMap.Submit, keyMap.Select, keyMap.DiffBase}
+	}
+	l.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keyMap.Submit, keyMap.Run, keyMap.Select, keyMap.ToggleLeft, keyMap.ToggleRight, keyMap.DiffBase, keyMap.DiffRef, keyMap.Copy, keyMap.Preview, keyMap.Edit}
+	}
+	return l
+}
+
+func (m model) handleChanges(msg changesMsg) (tea.Model, tea.Cmd) {
+	if msg.base != m.changeBase {
+		// A newer base was picked while this diff was running
+		return m, nil
+	}
+	m.changesLabel = msg.label
+	m.changesErr = msg.err
+	m.changes = msg.changes
+	m.changeSources = msg.sources
+	m.applyChanges()
+	m.refreshLists()
+	return m, nil
+}
+
+// applyChanges marks the spec files the diff touches and the tests whose
+// lines it intersects, and fills the Changed list with them. A test spans
+// from its line to the end of its body, and never past the next test in the
+// same file.
+func (m *model) applyChanges() {
+	root := m.rootDir
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+
+	changedTests := map[string]bool{}
+	var changed []item
+	for i, file := range m.originalFiles {
+		path := filepath.Join(root, file.title)
+		ranges := m.changes[path]
+		m.originalFiles[i].changed = len(ranges) > 0
+		if len(ranges) == 0 {
+			continue
+		}
+		changed = append(changed, m.originalFiles[i])
+
+		specs := append([]item{}, m.fileToSpecs[file.title]...)
+		sort.SliceStable(specs, func(a, b int) bool { return specs[a].line < specs[b].line })
+		for j, spec := range specs {
+			// Parameterised tests share a line, so look past them
+			end := math.MaxInt
+			for _, next := range specs[j+1:] {
+				if next.line > spec.line {
+					end = next.line - 1
+					break
+				}
+			}
+			// Otherwise helpers after the last test would count as part of it
+			if src, ok := m.changeSources[path]; ok {
+				if body, ok := blockEnd(src, spec.line); ok {
+					end = min(end, body)
+				}
+			}
+			for _, r := range ranges {
+				if r.start <= end && r.end >= spec.line {
+					changedTests[spec.description] = true
+					break
+				}
+			}
+		}
+	}
+
+	for i, it := range m.originalTests {
+		m.originalTests[i].changed = changedTests[it.description]
+	}
+	// Tests follow the file they are in
+	var withTests []item
+	for _, file := range changed {
+		file.changed = true
+		withTests = append(withTests, file)
+		for _, it := range m.originalTests {
+			if it.changed && specFile(it) == file.title {
+				withTests = append(withTests, it)
+			}
+		}
+	}
+	m.originalChanged = withTests
+}
+
+// changesSummary describes the Changed list above the lists.
+func (m model) changesSummary() string {
+	if m.changesErr != nil {
+		return statusRemoveStyle("Changed: " + m.changesErr.Error())
+	}
+	if m.changes == nil {
+		return "Changed: comparing…"
+	}
+	files, tests := 0, 0
+	for _, it := range m.originalChanged {
+		if it.source == "Files" {
+			files++
+		} else {
+			tests++
+		}
+	}
+	return fmt.Sprintf("Changed vs %s · %d file%s, %d test%s", changedStyle.Render(m.changesLabel), files, plural(files), tests, plural(tests))
+}
+
+// setDiffBase compares with base and updates the Changed list.
+func (m *model) setDiffBase(base diffBase) tea.Cmd {
+	m.changeBase = base
+	if base.kind == diffRef {
+		m.diffRef = base.ref
+	}
+	m.changes = nil
+	m.changeSources = nil
+	m.changesErr = nil
+	return m.loadChanges()
+}
+
+func (m model) submitDiffRef(value string) (tea.Model, tea.Cmd) {
+	ref := strings.TrimSpace(value)
+	if ref == "" {
+		return m, nil
+	}
+	return m, m.setDiffBase(diffBase{kind: diffRef, ref: ref})
+}
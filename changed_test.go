@@ -0,0 +1,221 @@
+package main
+
+import (
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseDiff(t *testing.T) {
+	out := `diff --git a/tests/a.spec.ts b/tests/a.spec.ts
+--- a/tests/a.spec.ts
++++ b/tests/a.spec.ts
+@@ -8 +8 @@ test("two", () => {
+-  b()
++  bb()
+@@ -12,2 +13,0 @@
+-  c()
+-  d()
+diff --git a/tests/old.spec.ts b/tests/old.spec.ts
+--- a/tests/old.spec.ts
++++ /dev/null
+@@ -1,3 +0,0 @@
+-test("gone")
+`
+	want := map[string][]lineRange{"tests/a.spec.ts": {{8, 8}, {13, 13}}}
+	if got := parseDiff(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDiff = %v; want %v", got, want)
+	}
+}
+
+func TestApplyChanges_MarksFilesAndTests(t *testing.T) {
+	isolateUserDirs(t)
+	data := presetTestData()
+	data.Config.RootDir = "/repo/tests"
+	m := NewModel(data, nil, nil)
+
+	// Line 9 falls after "removes" starts on line 8
+	m.changes = map[string][]lineRange{"/repo/tests/cart.spec.ts": {{9, 9}}}
+	m.applyChanges()
+	m.refreshLists()
+
+	changed := m.lists[m.listIdx("Changed")].Items()
+	if len(changed) != 2 || changed[0].(item).title != "cart.spec.ts" || changed[1].(item).description != "cart.spec.ts:8" {
+		t.Fatalf("expected the file followed by its changed test, got %v", changed)
+	}
+	for _, it := range m.originalTests {
+		if it.changed != (it.description == "cart.spec.ts:8") {
+			t.Errorf("unexpected changed mark on %s", it.description)
+		}
+	}
+
+	// Selecting a changed test takes it off the Changed list too
+	m.focusedIdx = m.listIdx("Changed")
+	m.lists[m.focusedIdx].Select(1)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = updated.(model)
+	if len(m.lists[m.listIdx("Changed")].Items()) != 1 || len(m.lists[m.listIdx("Tests")].Items()) != 1 {
+		t.Errorf("expected the test to be selected from both lists")
+	}
+
+	// A change before the first test only touches the file
+	m.changes = map[string][]lineRange{"/repo/tests/cart.spec.ts": {{1, 1}}}
+	m.applyChanges()
+	if len(m.originalChanged) != 1 || !m.originalChanged[0].changed {
+		t.Errorf("expected only the file, got %v", m.originalChanged)
+	}
+}
+
+func TestApplyChanges_TestsSharingALine(t *testing.T) {
+	isolateUserDirs(t)
+	data := presetTestData()
+	data.Config.RootDir = "/repo/tests"
+	specs := data.Suites[0].Suites[0].Specs
+	data.Suites[0].Suites[0].Specs = []Spec{
+		{Title: "adds 1", File: "cart.spec.ts", Line: 3, Tests: []TestInstance{{ProjectName: "chromium"}}},
+		{Title: "adds 2", File: "cart.spec.ts", Line: 3, Tests: []TestInstance{{ProjectName: "chromium"}}},
+		specs[1],
+	}
+	m := NewModel(data, nil, nil)
+
+	// Line 10 is inside "removes", past both parameterised tests
+	m.changes = map[string][]lineRange{"/repo/tests/cart.spec.ts": {{10, 10}}}
+	m.applyChanges()
+	for _, it := range m.originalTests {
+		if it.changed != (it.title == "Cart › removes") {
+			t.Errorf("unexpected changed mark %v on %s", it.changed, it.title)
+		}
+	}
+}
+
+func TestGitChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	gitIn := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=pwgo", "-c", "user.email=pwgo@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	gitIn("init", "-q", "-b", "main")
+	os.WriteFile(filepath.Join(dir, "a.spec.ts"), []byte("test('one')\ntest('two')\n"), 0o644)
+	gitIn("add", ".")
+	gitIn("commit", "-q", "-m", "init")
+	gitIn("checkout", "-q", "-b", "feature")
+	os.WriteFile(filepath.Join(dir, "a.spec.ts"), []byte("test('one')\ntest('2')\n"), 0o644)
+	gitIn("commit", "-q", "-am", "rename")
+	os.WriteFile(filepath.Join(dir, "new.spec.ts"), []byte("test('new')\n"), 0o644)
+
+	top, _ := git(dir, "rev-parse", "--show-toplevel")
+	root := strings.TrimSpace(top)
+	cases := []struct {
+		base  diffBase
+		label string
+		files int
+	}{
+		{diffBase{kind: diffHead}, "HEAD", 1},
+		{diffBase{kind: diffMergeBase}, "merge-base with main", 2},
+		{diffBase{kind: diffRef, ref: "main"}, "main", 2},
+	}
+	for _, c := range cases {
+		label, changes, err := gitChanges(dir, c.base)
+		if err != nil {
+			t.Fatalf("gitChanges(%s) failed: %v", c.label, err)
+		}
+		if label != c.label || len(changes) != c.files {
+			t.Errorf("gitChanges(%s) = %s with %v", c.label, label, changes)
+		}
+		if r := changes[filepath.Join(root, "new.spec.ts")]; !reflect.DeepEqual(r, []lineRange{{1, math.MaxInt}}) {
+			t.Errorf("expected the untracked file to count as changed, got %v", r)
+		}
+	}
+	if _, _, err := gitChanges(dir, diffBase{kind: diffRef, ref: "missing"}); err == nil {
+		t.Errorf("expected an error for an unknown ref")
+	}
+}
+
+func TestDiffBase_Next(t *testing.T) {
+	b := diffBase{}
+	var kinds []diffBaseKind
+	for i := 0; i < 4; i++ {
+		b = b.next("release")
+		kinds = append(kinds, b.kind)
+	}
+	if !reflect.DeepEqual(kinds, []diffBaseKind{diffHead, diffMergeBase, diffRef, diffWorkingTree}) {
+		t.Errorf("unexpected cycle %v", kinds)
+	}
+	if next := (diffBase{kind: diffMergeBase}).next(""); next.kind != diffWorkingTree {
+		t.Errorf("expected to skip the ref before one is entered, got %v", next)
+	}
+}
+
+func TestBlockEnd(t *testing.T) {
+	src := []byte(`import { test } from '@playwright/test';
+
+test('adds', async ({ page }) => {
+  // a stray } in a comment
+  await page.fill('#q', "}");
+  await expect(page.locator(` + "`" + `
+    ${'}'}
+  ` + "`" + `)).toBeVisible();
+});
+
+function helper() {
+  return 1;
+}
+`)
+	if end, ok := blockEnd(src, 3); !ok || end != 9 {
+		t.Errorf("blockEnd(3) = %d, %v; want 9", end, ok)
+	}
+	if _, ok := blockEnd([]byte("test('cut short', () => {\n"), 1); ok {
+		t.Errorf("expected unbalanced brackets to report no end")
+	}
+}
+
+func TestApplyChanges_HelpersAfterTheLastTest(t *testing.T) {
+	isolateUserDirs(t)
+	data := presetTestData()
+	data.Config.RootDir = "/repo/tests"
+	m := NewModel(data, nil, nil)
+
+	// "removes" runs from line 8 to 10; the helper below it is not part of it
+	src := "\n" + strings.Repeat("\n", 6) + "  test('removes', () => {\n    expect(1).toBe(1);\n  });\n});\n\nfunction helper() {}\n"
+	m.changes = map[string][]lineRange{"/repo/tests/cart.spec.ts": {{13, 13}}}
+	m.changeSources = map[string][]byte{"/repo/tests/cart.spec.ts": []byte(src)}
+	m.applyChanges()
+	if len(m.originalChanged) != 1 {
+		t.Errorf("expected only the file to be changed, got %v", m.originalChanged)
+	}
+
+	m.changes = map[string][]lineRange{"/repo/tests/cart.spec.ts": {{9, 9}}}
+	m.applyChanges()
+	if len(m.originalChanged) != 2 {
+		t.Errorf("expected the file and the test to be changed, got %v", m.originalChanged)
+	}
+}
+
+func TestDiffBaseKeys_OnlyOnTheChangedList(t *testing.T) {
+	isolateUserDirs(t)
+	m := NewModel(presetTestData(), nil, nil)
+	m.focusedIdx = m.listIdx("Tests")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if updated.(model).changeBase != m.changeBase {
+		t.Errorf("expected c to leave the diff base alone outside the Changed list")
+	}
+
+	m.focusedIdx = m.listIdx("Changed")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if updated.(model).changeBase == m.changeBase {
+		t.Errorf("expected c to change the diff base on the Changed list")
+	}
+}
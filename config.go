@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileNames are checked in each directory from the cwd up to the root.
+var configFileNames = []string{".pwgo.yaml", ".pwgo.yml", ".pwgo.json"}
+
+// pwgoConfig holds team defaults shared through a .pwgo.yaml or .pwgo.json
+// file. Command-line flags override any value set here.
+type pwgoConfig struct {
+	Projects   []string `yaml:"projects" json:"projects"`
+	Config     string   `yaml:"config" json:"config"`
+	Configs    []string `yaml:"configs" json:"configs"`
+	Grep       string   `yaml:"grep" json:"grep"`
+	GrepInvert string   `yaml:"grepInvert" json:"grepInvert"`
+	Args       []string `yaml:"args" json:"args"`
+	Runner     string   `yaml:"runner" json:"runner"`
+	UI         uiConfig `yaml:"ui" json:"ui"`
+	// Envs are named sets of environment variables to run tests with
+	Envs map[string]map[string]string `yaml:"envs" json:"envs"`
+	Env  string                       `yaml:"env" json:"env"`
+
+	path string
+}
+
+type uiConfig struct {
+	TreeView    bool `yaml:"treeView" json:"treeView"`
+	HideSkipped bool `yaml:"hideSkipped" json:"hideSkipped"`
+	Watch       bool `yaml:"watch" json:"watch"`
+	Stay        bool `yaml:"stay" json:"stay"`
+}
+
+var userConfig pwgoConfig
+
+// findConfigFile walks up from dir looking for a pwgo config file.
+func findConfigFile(dir string) (string, bool) {
+	for {
+		for _, name := range configFileNames {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+func loadConfigFile(path string) (pwgoConfig, error) {
+	var cfg pwgoConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	if filepath.Ext(path) == ".json" {
+		err = json.Unmarshal(data, &cfg)
+	} else {
+		err = yaml.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	cfg.path = path
+	return cfg, nil
+}
+
+// discoverConfig loads the nearest config file above the cwd, if any.
+func discoverConfig() (pwgoConfig, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return pwgoConfig{}, err
+	}
+	path, ok := findConfigFile(cwd)
+	if !ok {
+		return pwgoConfig{}, nil
+	}
+	return loadConfigFile(path)
+}
+
+// playwrightConfig resolves the config path relative to the file it was set
+// in, so the same value works from any directory in the repo.
+func (c pwgoConfig) playwrightConfig() string {
+	if c.Config == "" || filepath.IsAbs(c.Config) || c.path == "" {
+		return c.Config
+	}
+	return filepath.Join(filepath.Dir(c.path), c.Config)
+}
+
+// playwrightConfigs expands the configs patterns relative to the file they
+// were set in.
+func (c pwgoConfig) playwrightConfigs() ([]string, error) {
+	var paths []string
+	for _, pattern := range c.Configs {
+		if !filepath.IsAbs(pattern) && c.path != "" {
+			pattern = filepath.Join(filepath.Dir(c.path), pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid configs pattern %q: %w", pattern, err)
+		}
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	old, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(old) })
+}
+
+func TestFindConfigFile_WalksUp(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "packages", "web")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(root, ".pwgo.yaml")
+	if err := os.WriteFile(want, []byte("projects: [chromium]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := findConfigFile(nested)
+	if !ok || got != want {
+		t.Errorf("findConfigFile = %q, %v; want %q", got, ok, want)
+	}
+}
+
+func TestLoadConfigFile_YAMLAndJSON(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, ".pwgo.yaml")
+	os.WriteFile(yamlPath, []byte(`
+projects: [chromium, firefox]
+config: e2e/playwright.config.ts
+grep: "@smoke"
+grepInvert: "@slow"
+args: ["--workers=2"]
+runner: pnpm exec playwright
+ui:
+  treeView: true
+  hideSkipped: true
+`), 0o644)
+
+	cfg, err := loadConfigFile(yamlPath)
+	if err != nil {
+		t.Fatalf("loadConfigFile failed: %v", err)
+	}
+	if len(cfg.Projects) != 2 || cfg.Grep != "@smoke" || cfg.GrepInvert != "@slow" || cfg.Runner != "pnpm exec playwright" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if !cfg.UI.TreeView || !cfg.UI.HideSkipped {
+		t.Errorf("expected UI preferences to be loaded, got %+v", cfg.UI)
+	}
+	if got, want := cfg.playwrightConfig(), filepath.Join(dir, "e2e", "playwright.config.ts"); got != want {
+		t.Errorf("playwrightConfig = %q; want %q", got, want)
+	}
+
+	jsonPath := filepath.Join(dir, ".pwgo.json")
+	os.WriteFile(jsonPath, []byte("{\n\t\"args\": [\"--headed\"],\n\t\"ui\": {\"treeView\": true}\n}"), 0o644)
+	cfg, err = loadConfigFile(jsonPath)
+	if err != nil {
+		t.Fatalf("loadConfigFile failed: %v", err)
+	}
+	if len(cfg.Args) != 1 || !cfg.UI.TreeView {
+		t.Errorf("unexpected JSON config: %+v", cfg)
+	}
+
+	badPath := filepath.Join(dir, "bad.yaml")
+	os.WriteFile(badPath, []byte("projects: [unterminated"), 0o644)
+	if _, err := loadConfigFile(badPath); err == nil {
+		t.Errorf("expected an error for invalid YAML")
+	}
+}
+
+func TestPrepareData_ConfigDefaults(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := writeTempJSON(t, PlaywrightJSON{Suites: []Suite{{Title: "a.spec.ts", File: "a.spec.ts"}}})
+	defer os.Remove(jsonPath)
+	os.WriteFile(filepath.Join(dir, ".pwgo.yaml"), []byte(`
+projects: [webkit]
+args: ["--workers=1"]
+`), 0o644)
+	chdir(t, dir)
+
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	os.Args = []string{"cmd", "--json-data-path", jsonPath, "--headed"}
+	_, projects, extraArgs, err := prepareData()
+	if err != nil {
+		t.Fatalf("prepareData failed: %v", err)
+	}
+	if len(projects) != 1 || projects[0] != "webkit" {
+		t.Errorf("expected projects from config, got %v", projects)
+	}
+	if len(extraArgs) != 2 || extraArgs[0] != "--workers=1" || extraArgs[1] != "--headed" {
+		t.Errorf("expected config args before CLI args, got %v", extraArgs)
+	}
+
+	os.Args = []string{"cmd", "--json-data-path", jsonPath, "--project", "chromium"}
+	_, projects, _, err = prepareData()
+	if err != nil {
+		t.Fatalf("prepareData failed: %v", err)
+	}
+	if len(projects) != 1 || projects[0] != "chromium" {
+		t.Errorf("expected CLI projects to override config, got %v", projects)
+	}
+}
+
+func TestPrepareData_CLIOverridesConfig(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := writeTempJSON(t, PlaywrightJSON{Suites: []Suite{{Title: "a.spec.ts", File: "a.spec.ts"}}})
+	defer os.Remove(jsonPath)
+	os.WriteFile(filepath.Join(dir, ".pwgo.yaml"), []byte(`
+args: ["--workers", "1", "--headed", "--retries=2"]
+ui:
+  treeView: true
+  watch: true
+  stay: true
+`), 0o644)
+	chdir(t, dir)
+
+	oldArgs, oldWatch, oldStay, oldConfig := os.Args, watchFiles, stayAfterRun, userConfig
+	defer func() { os.Args, watchFiles, stayAfterRun, userConfig = oldArgs, oldWatch, oldStay, oldConfig }()
+	watchFiles, stayAfterRun = false, false
+
+	os.Args = []string{"cmd", "--json-data-path", jsonPath, "-j", "4", "--retries=0", "--no-watch", "--no-tree"}
+	_, _, extraArgs, err := prepareData()
+	if err != nil {
+		t.Fatalf("prepareData failed: %v", err)
+	}
+	want := []string{"--headed", "-j", "4", "--retries=0"}
+	if !reflect.DeepEqual(extraArgs, want) {
+		t.Errorf("extraArgs = %v; want %v", extraArgs, want)
+	}
+	if watchFiles || userConfig.UI.TreeView {
+		t.Errorf("expected --no-watch and --no-tree to switch off the config settings")
+	}
+	if !stayAfterRun {
+		t.Errorf("expected stay from the config to be kept")
+	}
+}
+
+func TestPrepareData_NoGrepClearsConfigGrep(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := writeTempJSON(t, PlaywrightJSON{Suites: []Suite{{Title: "a.spec.ts", File: "a.spec.ts"}}})
+	defer os.Remove(jsonPath)
+	os.WriteFile(filepath.Join(dir, ".pwgo.yaml"), []byte(`
+grep: "@smoke"
+grepInvert: "@flaky"
+`), 0o644)
+	chdir(t, dir)
+
+	oldArgs, oldOptions := os.Args, currentListOptions
+	defer func() { os.Args, currentListOptions = oldArgs, oldOptions }()
+
+	os.Args = []string{"cmd", "--json-data-path", jsonPath, "--no-grep"}
+	if _, _, _, err := prepareData(); err != nil {
+		t.Fatalf("prepareData failed: %v", err)
+	}
+	if currentListOptions.grep != "" || currentListOptions.grepInvert != "@flaky" {
+		t.Errorf("expected --no-grep to clear only grep, got %q and %q", currentListOptions.grep, currentListOptions.grepInvert)
+	}
+}
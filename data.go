@@ -3,9 +3,9 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -17,11 +17,42 @@ import (
 var (
 	configPath   string
 	jsonDataPath string
+	// runnerCommand is the template used to invoke the Playwright CLI
+	runnerCommand string
+	// printOnly prints the Playwright command on enter instead of running it
+	printOnly bool
+	// watchFiles re-lists tests when spec files change
+	watchFiles bool
+	// noCache always runs --list instead of using the saved listing
+	noCache bool
+	// stayAfterRun returns to the picker when Playwright exits
+	stayAfterRun bool
 )
 
+// listOptions are the --list filters given at startup, kept so the data can
+// be loaded again when files change.
+type listOptions struct {
+	projects    []string
+	onlyChanged bool
+	lastFailed  bool
+	grep        string
+	grepInvert  string
+}
+
+var currentListOptions listOptions
+
 type PlaywrightJSON struct {
+	Config PWConfig  `json:"config"`
 	Suites []Suite   `json:"suites"`
 	Errors []PWError `json:"errors"`
+	// Workspace describes the configs a monorepo listing was merged from
+	Workspace []workspaceConfig `json:"workspace,omitempty"`
+}
+
+// PWConfig is the part of the resolved Playwright config pwgo uses. Spec file
+// paths in the report are relative to RootDir.
+type PWConfig struct {
+	RootDir string `json:"rootDir"`
 }
 
 type PWError struct {
@@ -29,13 +60,39 @@ type PWError struct {
 	Stack   string `json:"stack"`
 }
 
+// playwrightErrors are the errors Playwright reported while listing tests,
+// kept whole so the TUI can show each one.
+type playwrightErrors []PWError
+
+func (e playwrightErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, pe := range e {
+		messages[i] = "- " + pe.Message
+	}
+	return fmt.Sprintf("Playwright returned %d error(s):\n%s", len(e), strings.Join(messages, "\n"))
+}
+
 type Annotation struct {
-	Type string `json:"type"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
 }
 
+// annotationOrder lists the annotations surfaced on test items, in display order.
+var annotationOrder = []string{"skip", "fixme", "fail", "slow"}
+
 type TestInstance struct {
 	ProjectName string       `json:"projectName"`
 	Annotations []Annotation `json:"annotations"`
+	Status      string       `json:"status,omitempty"`
+	Results     []TestResult `json:"results,omitempty"`
+}
+
+// TestResult is a single attempt of a test in a JSON reporter run.
+type TestResult struct {
+	Status   string    `json:"status"`
+	Duration int64     `json:"duration"`
+	Retry    int       `json:"retry"`
+	Errors   []PWError `json:"errors"`
 }
 
 type Spec struct {
@@ -54,50 +111,67 @@ type Suite struct {
 	Specs  []Spec  `json:"specs"`
 }
 
-func initData(projects []string, onlyChanged, lastFailed bool, grep, grepInvert string) (PlaywrightJSON, error) {
-	args := []string{"playwright", "test", "--list", "--reporter=json"}
-	if onlyChanged {
+// errNoTests is returned when Playwright lists no tests at all.
+var errNoTests = errors.New("No tests found")
+
+// listArgs builds the Playwright arguments that list tests as JSON.
+func listArgs(opts listOptions) []string {
+	return configListArgs(opts, configPath)
+}
+
+func configListArgs(opts listOptions, config string) []string {
+	args := []string{"test", "--list", "--reporter=json"}
+	if opts.onlyChanged {
 		args = append(args, "--only-changed")
 	}
-	if lastFailed {
+	if opts.lastFailed {
 		args = append(args, "--last-failed")
 	}
-	if configPath != "" {
-		args = append(args, "--config", configPath)
+	if config != "" {
+		args = append(args, "--config", config)
 	}
-	if grep != "" {
-		args = append(args, "--grep", grep)
+	if opts.grep != "" {
+		args = append(args, "--grep", opts.grep)
 	}
-	if grepInvert != "" {
-		args = append(args, "--grep-invert", grepInvert)
+	if opts.grepInvert != "" {
+		args = append(args, "--grep-invert", opts.grepInvert)
 	}
-	for _, p := range projects {
+	for _, p := range opts.projects {
 		args = append(args, "--project", p)
 	}
+	return args
+}
+
+func initData(opts listOptions) (PlaywrightJSON, error) {
+	return listConfig(opts, configPath, "")
+}
 
-	cmd := exec.Command("npx", args...)
-	var out bytes.Buffer
+// listConfig lists the tests of a config with Playwright running in dir.
+func listConfig(opts listOptions, config, dir string) (PlaywrightJSON, error) {
+	cmd := playwrightCommand(configListArgs(opts, config))
+	runIn(cmd, dir)
+	var out, stderr bytes.Buffer
 	cmd.Stdout = &out
-	cmd.Stderr = nil
+	cmd.Stderr = &stderr
 
 	err := cmd.Run()
 
 	var pwData PlaywrightJSON
 	if jsonErr := json.Unmarshal(out.Bytes(), &pwData); jsonErr != nil {
-		// If it's not even valid JSON, return the raw output + error
+		// If it's not even valid JSON, whatever Playwright printed explains why
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return PlaywrightJSON{}, fmt.Errorf("failed to parse JSON output: %w\n%s", jsonErr, detail)
+		}
 		return PlaywrightJSON{}, fmt.Errorf("failed to parse JSON output: %w", jsonErr)
 	}
 
-	if len(pwData.Suites) == 0 {
-		return pwData, fmt.Errorf("No tests found")
+	// Errors come first since a broken spec file usually also means no tests
+	if len(pwData.Errors) > 0 {
+		return pwData, playwrightErrors(pwData.Errors)
 	}
 
-	if len(pwData.Errors) > 0 {
-		fmt.Println("Playwright Errors:")
-		for _, e := range pwData.Errors {
-			fmt.Printf("- %s\n", e.Message)
-		}
-		return pwData, fmt.Errorf("Playwright returned %d error(s)", len(pwData.Errors))
+	if len(pwData.Suites) == 0 {
+		return pwData, errNoTests
 	}
 
 	if err != nil {
@@ -107,8 +181,86 @@ func initData(projects []string, onlyChanged, lastFailed bool, grep, grepInvert
 	return pwData, nil
 }
 
+// newSpecItem builds the Tests list item for a spec.
+func newSpecItem(spec Spec, testTitle string) item {
+	found := map[string]struct{}{}
+	for _, test := range spec.Tests {
+		for _, a := range test.Annotations {
+			found[a.Type] = struct{}{}
+		}
+	}
+
+	var annotations []string
+	for _, a := range annotationOrder {
+		if _, ok := found[a]; ok {
+			annotations = append(annotations, a)
+		}
+	}
+
+	var projects []string
+	for _, test := range spec.Tests {
+		if !containsString(projects, test.ProjectName) {
+			projects = append(projects, test.ProjectName)
+		}
+	}
+
+	return item{
+		title:       testTitle,
+		description: fmt.Sprintf("%s:%d", spec.File, spec.Line),
+		line:        spec.Line,
+		source:      "Tests",
+		tags:        spec.Tags,
+		annotations: annotations,
+		projects:    projects,
+		skipped:     hasAnnotation(annotations, "skip") || hasAnnotation(annotations, "fixme"),
+	}
+}
+
+func hasAnnotation(annotations []string, want string) bool {
+	for _, a := range annotations {
+		if a == want {
+			return true
+		}
+	}
+	return false
+}
+
+// runnableCount returns how many of the specs are not skipped or fixme.
+func runnableCount(specs []item) int {
+	n := 0
+	for _, s := range specs {
+		if !s.skipped {
+			n++
+		}
+	}
+	return n
+}
+
+// countDescription formats the "N tests across M projects" helper, noting how
+// many are runnable when some are skipped.
+func countDescription(specs []item, projectCount int) string {
+	count := len(specs) * projectCount
+	desc := fmt.Sprintf("%d test%s across %d project%s", count, plural(count), projectCount, plural(projectCount))
+	if runnable := runnableCount(specs) * projectCount; runnable != count {
+		desc += fmt.Sprintf(" (%d runnable)", runnable)
+	}
+	return desc
+}
+
+// suiteTitle appends a suite's title to its parent's, skipping file-level suites.
+func suiteTitle(parent string, suite Suite) string {
+	fullTitle := parent
+	if suite.Title != "" && suite.Title != suite.File && filepath.Base(suite.Title) != filepath.Base(suite.File) {
+		if fullTitle != "" {
+			fullTitle += " › "
+		}
+		fullTitle += suite.Title
+	}
+	return fullTitle
+}
+
 func collectData(
-	suite Suite, suiteTitle string,
+	suite Suite, parentTitle string,
 	testItems, fileItems *[]list.Item,
 	tagSet map[string]struct{},
 	tagToSpecs map[string][]item,
@@ -117,14 +269,9 @@ func collectData(
 	fileToSpecs map[string][]item,
 	fileToProjects map[string]map[string]struct{},
 	tagToProjects map[string]map[string]struct{},
+	projectToSpecs map[string][]item,
 ) {
-	fullTitle := suiteTitle
-	if suite.Title != "" && suite.Title != suite.File && filepath.Base(suite.Title) != filepath.Base(suite.File) {
-		if fullTitle != "" {
-			fullTitle += " › "
-		}
-		fullTitle += suite.Title
-	}
+	fullTitle := suiteTitle(parentTitle, suite)
 
 	for _, spec := range suite.Specs {
 		testTitle := fullTitle
@@ -150,13 +297,7 @@ func collectData(
 
 		testKey := fmt.Sprintf("%s|%s|%d", spec.Title, spec.File, spec.Line)
 		if _, exists := seenTests[testKey]; !exists {
-			specItem := item{
-				title:       testTitle,
-				description: fmt.Sprintf("%s:%d", spec.File, spec.Line),
-				line:        spec.Line,
-				source:      "Tests",
-				tags:        spec.Tags,
-			}
+			specItem := newSpecItem(spec, testTitle)
 			*testItems = append(*testItems, specItem)
 			seenTests[testKey] = struct{}{}
 
@@ -166,6 +307,13 @@ func collectData(
 			}
 
 			fileToSpecs[spec.File] = append(fileToSpecs[spec.File], specItem)
+
+			for _, test := range spec.Tests {
+				if test.ProjectName == "" {
+					continue
+				}
+				projectToSpecs[test.ProjectName] = append(projectToSpecs[test.ProjectName], specItem)
+			}
 		}
 
 		if spec.File != "" {
@@ -179,17 +327,43 @@ func collectData(
 	}
 
 	for _, child := range suite.Suites {
-		collectData(child, fullTitle, testItems, fileItems, tagSet, tagToSpecs, seenTests, fileTagMap, fileToSpecs, fileToProjects, tagToProjects)
+		collectData(child, fullTitle, testItems, fileItems, tagSet, tagToSpecs, seenTests, fileTagMap, fileToSpecs, fileToProjects, tagToProjects, projectToSpecs)
 	}
 }
 
 func prepareData() (PlaywrightJSON, []string, []string, error) {
+	return prepareDataFrom(os.Args[1:])
+}
+
+// prepareDataFrom parses pwgo flags from args, merges in the config file and
+// loads the Playwright --list data.
+func prepareDataFrom(args []string) (PlaywrightJSON, []string, []string, error) {
+	projects, extraArgs, err := parseArgs(args)
+	if err != nil {
+		return PlaywrightJSON{}, nil, nil, err
+	}
+	if pwData, fresh, ok := readListCache(currentListOptions); ok && fresh {
+		return pwData, projects, extraArgs, nil
+	}
+	pwData, err := loadData(currentListOptions)
+	if err != nil {
+		return pwData, nil, nil, err
+	}
+	return pwData, projects, extraArgs, nil
+}
+
+// parseArgs parses pwgo flags from args and merges in the config file,
+// setting currentListOptions for loadData. It returns the projects and the
+// arguments passed through to Playwright.
+func parseArgs(args []string) ([]string, []string, error) {
 	projects := []string{}
-	var onlyChanged, lastFailed bool
-	var extraArgs []string
-	var grep, grepInvert string
+	var onlyChanged, lastFailed, allConfigs bool
+	// The --no-* flags switch off settings the config file turns on
+	var noWatch, noStay, noTree, noHideSkipped, noGrep, noGrepInvert bool
+	var extraArgs, configs []string
+	var grep, grepInvert, runner string
 
-	for _, arg := range os.Args[1:] {
+	for _, arg := range args {
 		if arg == "--help" || arg == "-h" {
 			printHelp()
 			os.Exit(0)
@@ -197,13 +371,13 @@ func prepareData() (PlaywrightJSON, []string, []string, error) {
 	}
 
 	// Parse command-line flags
-	for i := 1; i < len(os.Args); i++ {
-		arg := os.Args[i]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
 		switch {
-		case arg == "--project" && i+1 < len(os.Args):
+		case arg == "--project" && i+1 < len(args):
 			i++
-			for i < len(os.Args) && !strings.HasPrefix(os.Args[i], "-") {
-				projects = append(projects, os.Args[i])
+			for i < len(args) && !strings.HasPrefix(args[i], "-") {
+				projects = append(projects, args[i])
 				i++
 			}
 			i--
@@ -213,33 +387,76 @@ func prepareData() (PlaywrightJSON, []string, []string, error) {
 				projects = append(projects, p)
 			}
 		case arg == "-g" || arg == "--grep":
-			if i+1 < len(os.Args) {
-				grep = os.Args[i+1]
+			if i+1 < len(args) {
+				grep = args[i+1]
 				i++
 			}
 		case strings.HasPrefix(arg, "--grep="):
 			grep = strings.TrimPrefix(arg, "--grep=")
 		case arg == "-gv" || arg == "--grep-invert":
-			if i+1 < len(os.Args) {
-				grepInvert = os.Args[i+1]
+			if i+1 < len(args) {
+				grepInvert = args[i+1]
 				i++
 			}
 		case strings.HasPrefix(arg, "--grep-invert="):
 			grepInvert = strings.TrimPrefix(arg, "--grep-invert=")
+		case arg == "--no-grep":
+			noGrep = true
+		case arg == "--no-grep-invert":
+			noGrepInvert = true
 		case arg == "--json-data-path":
-			if i+1 < len(os.Args) {
-				jsonDataPath = os.Args[i+1]
+			if i+1 < len(args) {
+				jsonDataPath = args[i+1]
 				i++
 			}
 		case strings.HasPrefix(arg, "--json-data-path="):
 			jsonDataPath = strings.TrimPrefix(arg, "--json-data-path=")
 		case arg == "-c" || arg == "--config":
-			if i+1 < len(os.Args) {
-				configPath = os.Args[i+1]
+			if i+1 < len(args) {
+				configs = append(configs, args[i+1])
 				i++
 			}
 		case strings.HasPrefix(arg, "--config="):
-			configPath = strings.TrimPrefix(arg, "--config=")
+			configs = append(configs, strings.TrimPrefix(arg, "--config="))
+		case arg == "--all-configs":
+			allConfigs = true
+		case arg == "--runner":
+			if i+1 < len(args) {
+				runner = args[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--runner="):
+			runner = strings.TrimPrefix(arg, "--runner=")
+		case arg == "--preset":
+			if i+1 < len(args) {
+				presetName = args[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--preset="):
+			presetName = strings.TrimPrefix(arg, "--preset=")
+		case arg == "--env":
+			if i+1 < len(args) {
+				envName = args[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--env="):
+			envName = strings.TrimPrefix(arg, "--env=")
+		case arg == "--print" || arg == "--dry-run":
+			printOnly = true
+		case arg == "--watch":
+			watchFiles = true
+		case arg == "--no-watch":
+			noWatch = true
+		case arg == "--no-cache":
+			noCache = true
+		case arg == "--stay":
+			stayAfterRun = true
+		case arg == "--no-stay":
+			noStay = true
+		case arg == "--no-tree":
+			noTree = true
+		case arg == "--no-hide-skipped":
+			noHideSkipped = true
 		case arg == "--only-changed":
 			onlyChanged = true
 		case arg == "--last-failed":
@@ -249,30 +466,151 @@ func prepareData() (PlaywrightJSON, []string, []string, error) {
 		}
 	}
 
-	var pwData PlaywrightJSON
+	// Fill in anything not given on the command line from the config file
+	cfg, err := discoverConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading config: %w", err)
+	}
+	userConfig = cfg
+	if len(projects) == 0 {
+		projects = append(projects, cfg.Projects...)
+	}
+	if err := selectConfigs(configs, allConfigs, cfg); err != nil {
+		return nil, nil, err
+	}
+	if grep == "" && !noGrep {
+		grep = cfg.Grep
+	}
+	if grepInvert == "" && !noGrepInvert {
+		grepInvert = cfg.GrepInvert
+	}
+	if runner == "" {
+		runner = cfg.Runner
+	}
+	if cfg.UI.Watch {
+		watchFiles = true
+	}
+	if cfg.UI.Stay {
+		stayAfterRun = true
+	}
+	if noWatch {
+		watchFiles = false
+	}
+	if noStay {
+		stayAfterRun = false
+	}
+	if noTree {
+		userConfig.UI.TreeView = false
+	}
+	if noHideSkipped {
+		userConfig.UI.HideSkipped = false
+	}
+	if runner == "" {
+		cwd, _ := os.Getwd()
+		runner = detectRunner(cwd)
+	}
+	if _, _, err := runnerArgv(runner, nil); err != nil {
+		return nil, nil, fmt.Errorf("invalid runner: %w", err)
+	}
+	runnerCommand = runner
 
-	if jsonDataPath != "" {
-		data, readErr := os.ReadFile(jsonDataPath)
-		if readErr != nil {
-			return pwData, nil, nil, fmt.Errorf("error reading JSON file at %s: %w", jsonDataPath, readErr)
+	if envName == "" {
+		envName = cfg.Env
+	}
+	if _, ok := cfg.Envs[envName]; envName != "" && !ok {
+		return nil, nil, fmt.Errorf("env %q not found in the config file", envName)
+	}
+
+	if presetName != "" {
+		presets, err := loadPresets(presetsPath())
+		if err != nil {
+			return nil, nil, fmt.Errorf("error loading presets: %w", err)
 		}
-		if jsonErr := json.Unmarshal(data, &pwData); jsonErr != nil {
-			return pwData, nil, nil, fmt.Errorf("error parsing JSON data from file: %w", jsonErr)
+		if _, ok := presets[presetName]; !ok {
+			return nil, nil, fmt.Errorf("preset %q not found in %s", presetName, presetsPath())
 		}
-	} else {
-		var err error
-		pwData, err = initData(projects, onlyChanged, lastFailed, grep, grepInvert)
+	}
+	extraArgs = append(overriddenArgsRemoved(cfg.Args, extraArgs), extraArgs...)
+
+	currentListOptions = listOptions{
+		projects:    projects,
+		onlyChanged: onlyChanged,
+		lastFailed:  lastFailed,
+		grep:        grep,
+		grepInvert:  grepInvert,
+	}
+	return projects, extraArgs, nil
+}
+
+// overriddenArgsRemoved drops the config file arguments whose flag is also
+// given on the command line, along with their values, so the command line
+// replaces them rather than repeating them.
+func overriddenArgsRemoved(cfgArgs, cliArgs []string) []string {
+	var kept []string
+	for i := 0; i < len(cfgArgs); i++ {
+		arg := cfgArgs[i]
+		if !strings.HasPrefix(arg, "-") {
+			kept = append(kept, arg)
+			continue
+		}
+		name, _, hasValue := strings.Cut(arg, "=")
+		if !hasFlag(cliArgs, flagNames(name)...) {
+			kept = append(kept, arg)
+			continue
+		}
+		if !hasValue && i+1 < len(cfgArgs) && !strings.HasPrefix(cfgArgs[i+1], "-") {
+			i++
+		}
+	}
+	return kept
+}
+
+// flagNames returns flag with its short form, for the Playwright flags that
+// have one.
+func flagNames(flag string) []string {
+	switch flag {
+	case "--workers", "-j":
+		return []string{"--workers", "-j"}
+	case "--max-failures", "-x":
+		return []string{"--max-failures", "-x"}
+	}
+	return []string{flag}
+}
+
+// loadData reads the --json-data-path file if one was given, otherwise runs
+// Playwright's --list.
+func loadData(opts listOptions) (PlaywrightJSON, error) {
+	var pwData PlaywrightJSON
+	if jsonDataPath != "" {
+		data, err := os.ReadFile(jsonDataPath)
 		if err != nil {
-			return pwData, nil, nil, fmt.Errorf("error initializing data: %w", err)
+			return pwData, fmt.Errorf("error reading JSON file at %s: %w", jsonDataPath, err)
 		}
+		if err := json.Unmarshal(data, &pwData); err != nil {
+			return pwData, fmt.Errorf("error parsing JSON data from file: %w", err)
+		}
+		return pwData, nil
 	}
 
-	return pwData, projects, extraArgs, nil
+	var err error
+	if len(workspaceConfigs) > 0 {
+		pwData, err = listWorkspace(opts)
+	} else {
+		pwData, err = initData(opts)
+	}
+	if err != nil {
+		return pwData, fmt.Errorf("error initializing data: %w", err)
+	}
+	// A cache that cannot be written only costs the next startup some time
+	writeListCache(opts, pwData)
+	return pwData, nil
 }
 
-func buildLists(pwData PlaywrightJSON) (
-	list.Model, list.Model, list.Model,
-	map[string][]item, map[string][]item,
+// buildLists aggregates a listing into the Tests, Files, Tags and Projects
+// lists, with runtime estimates from timings.
+func buildLists(pwData PlaywrightJSON, timings testTimings) (
+	list.Model, list.Model, list.Model, list.Model,
+	map[string][]item, map[string][]item, map[string][]item,
 ) {
 	var testItems, fileItems []list.Item
 	tagSet := map[string]struct{}{}
@@ -282,9 +620,15 @@ func buildLists(pwData PlaywrightJSON) (
 	fileTagMap := map[string]map[string]struct{}{}
 	fileToProjects := map[string]map[string]struct{}{}
 	tagToProjects := map[string]map[string]struct{}{}
+	projectToSpecs := map[string][]item{}
 
 	for _, suite := range pwData.Suites {
-		collectData(suite, "", &testItems, &fileItems, tagSet, tagToSpecs, seenTests, fileTagMap, fileToSpecs, fileToProjects, tagToProjects)
+		collectData(suite, "", &testItems, &fileItems, tagSet, tagToSpecs, seenTests, fileTagMap, fileToSpecs, fileToProjects, tagToProjects, projectToSpecs)
+	}
+	for i, li := range testItems {
+		it := li.(item)
+		it.estimate = timings.estimate([]item{it}, nil)
+		testItems[i] = it
 	}
 
 	uniqueFileMap := map[string]struct{}{}
@@ -301,57 +645,99 @@ func buildLists(pwData PlaywrightJSON) (
 		}
 		sort.Strings(tags)
 
-		count := len(fileToSpecs[file])
 		projectCount := len(fileToProjects[file]) // Use pre-collected projects count
 
 		uniqueFiles = append(uniqueFiles, item{
 			title:       file,
 			source:      "Files",
 			tags:        tags,
-			description: fmt.Sprintf("%d test%s across %d project%s", count*projectCount, plural(count*projectCount), projectCount, plural(projectCount)),
+			description: countDescription(fileToSpecs[file], projectCount),
+			estimate:    timings.estimate(fileToSpecs[file], nil),
+			skipped:     runnableCount(fileToSpecs[file]) == 0,
 		})
 	}
 
 	var tagItems []list.Item
 	for tag := range tagSet {
-		count := len(tagToSpecs[tag])
 		projectCount := len(tagToProjects[tag]) // Use pre-collected projects count
 
 		tagItems = append(tagItems, item{
 			title:       tag,
 			source:      "Tags",
-			description: fmt.Sprintf("%d test%s across %d project%s", count*projectCount, plural(count*projectCount), projectCount, plural(projectCount)),
+			description: countDescription(tagToSpecs[tag], projectCount),
+			estimate:    timings.estimate(tagToSpecs[tag], nil),
+			skipped:     runnableCount(tagToSpecs[tag]) == 0,
 		})
 	}
 
+	var projectItems []list.Item
+	for project, specs := range projectToSpecs {
+		files := map[string]struct{}{}
+		for _, specItem := range specs {
+			files[specFile(specItem)] = struct{}{}
+		}
+
+		desc := fmt.Sprintf("%d test%s across %d file%s", len(specs), plural(len(specs)), len(files), plural(len(files)))
+		if runnable := runnableCount(specs); runnable != len(specs) {
+			desc += fmt.Sprintf(" (%d runnable)", runnable)
+		}
+
+		projectItems = append(projectItems, item{
+			title:       project,
+			source:      "Projects",
+			description: desc,
+			skipped:     runnableCount(specs) == 0,
+		})
+	}
+	sort.Slice(projectItems, func(i, j int) bool {
+		return projectItems[i].(item).title < projectItems[j].(item).title
+	})
+
 	testList := list.New(testItems, list.NewDefaultDelegate(), 0, 0)
 	fileList := list.New(uniqueFiles, list.NewDefaultDelegate(), 0, 0)
 	tagList := list.New(tagItems, list.NewDefaultDelegate(), 0, 0)
+	projectList := list.New(projectItems, list.NewDefaultDelegate(), 0, 0)
 
 	testList.AdditionalShortHelpKeys = func() []key.Binding {
-		return []key.Binding{keyMap.Submit, keyMap.Select}
+		return []key.Binding{keyMap.Submit, keyMap.Run, keyMap.Select}
 	}
 	testList.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{keyMap.Submit, keyMap.Select, keyMap.ToggleLeft, keyMap.ToggleRight}
+		return []key.Binding{keyMap.Submit, keyMap.Run, keyMap.Select, keyMap.ToggleLeft, keyMap.ToggleRight, keyMap.TreeView, keyMap.Fold, keyMap.HideSkipped, keyMap.Copy, keyMap.Preview, keyMap.PreviewDown, keyMap.PreviewUp, keyMap.Edit, keyMap.Reload, keyMap.EditGrep, keyMap.Options, keyMap.Shards, keyMap.Env}
 	}
 
 	fileList.AdditionalShortHelpKeys = func() []key.Binding {
-		return []key.Binding{keyMap.Submit, keyMap.Select}
+		return []key.Binding{keyMap.Submit, keyMap.Run, keyMap.Select}
 	}
 	fileList.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{keyMap.Submit, keyMap.Select, keyMap.ToggleLeft, keyMap.ToggleRight}
+		return []key.Binding{keyMap.Submit, keyMap.Run, keyMap.Select, keyMap.ToggleLeft, keyMap.ToggleRight, keyMap.HideSkipped, keyMap.Copy, keyMap.Preview, keyMap.PreviewDown, keyMap.PreviewUp, keyMap.Edit, keyMap.Reload, keyMap.EditGrep, keyMap.Options, keyMap.Shards, keyMap.Env}
 	}
 
 	tagList.AdditionalShortHelpKeys = func() []key.Binding {
-		return []key.Binding{keyMap.Submit, keyMap.Select}
+		return []key.Binding{keyMap.Submit, keyMap.Run, keyMap.Select}
 	}
 	tagList.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{keyMap.Submit, keyMap.Select, keyMap.ToggleLeft, keyMap.ToggleRight}
+		return []key.Binding{keyMap.Submit, keyMap.Run, keyMap.Select, keyMap.ToggleLeft, keyMap.ToggleRight, keyMap.HideSkipped, keyMap.Copy, keyMap.Preview, keyMap.PreviewDown, keyMap.PreviewUp, keyMap.Edit, keyMap.Reload, keyMap.EditGrep, keyMap.Options, keyMap.Shards, keyMap.Env}
+	}
+
+	projectList.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keyMap.Submit, keyMap.Run, keyMap.Select}
+	}
+	projectList.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keyMap.Submit, keyMap.Run, keyMap.Select, keyMap.ToggleLeft, keyMap.ToggleRight, keyMap.HideSkipped, keyMap.Copy, keyMap.Preview, keyMap.PreviewDown, keyMap.PreviewUp, keyMap.Edit, keyMap.Reload, keyMap.EditGrep, keyMap.Options, keyMap.Shards, keyMap.Env}
 	}
 
 	testList.Title = "Tests"
 	fileList.Title = "Files"
 	tagList.Title = "Tags"
+	projectList.Title = "Projects"
+
+	return testList, fileList, tagList, projectList, tagToSpecs, fileToSpecs, projectToSpecs
+}
 
-	return testList, fileList, tagList, tagToSpecs, fileToSpecs
+// specFile returns the file portion of a test item's "file:line" description.
+func specFile(it item) string {
+	if i := strings.LastIndex(it.description, ":"); i >= 0 {
+		return it.description[:i]
+	}
+	return it.description
 }
@@ -93,8 +93,9 @@ func TestCollectData_Basic(t *testing.T) {
 	tagToProjects := map[string]map[string]struct{}{}
 	fileTagMap := map[string]map[string]struct{}{}
 	seenTests := map[string]struct{}{}
+	projectToSpecs := map[string][]item{}
 
-	collectData(testSuite, "", &testItems, &fileItems, tagSet, tagToSpecs, seenTests, fileTagMap, fileToSpecs, fileToProjects, tagToProjects)
+	collectData(testSuite, "", &testItems, &fileItems, tagSet, tagToSpecs, seenTests, fileTagMap, fileToSpecs, fileToProjects, tagToProjects, projectToSpecs)
 
 	if len(testItems) != 1 {
 		t.Errorf("expected 1 test item, got %d", len(testItems))
@@ -111,6 +112,9 @@ func TestCollectData_Basic(t *testing.T) {
 	if len(fileToProjects["spec.ts"]) != 2 {
 		t.Errorf("expected 2 projects for file, got %d", len(fileToProjects["spec.ts"]))
 	}
+	if len(projectToSpecs["chrome"]) != 1 || len(projectToSpecs["firefox"]) != 1 {
+		t.Errorf("expected 1 spec per project, got %v", projectToSpecs)
+	}
 }
 
 func TestBuildLists_SimpleSuite(t *testing.T) {
@@ -132,7 +136,7 @@ func TestBuildLists_SimpleSuite(t *testing.T) {
 		}},
 	}
 
-	testList, fileList, tagList, tagToSpecs, fileToSpecs := buildLists(pwData)
+	testList, fileList, tagList, _, tagToSpecs, fileToSpecs, _ := buildLists(pwData, nil)
 
 	if len(testList.Items()) != 1 {
 		t.Errorf("expected 1 test item, got %d", len(testList.Items()))
@@ -208,7 +212,7 @@ func TestBuildLists_MultipleSuites(t *testing.T) {
 		},
 	}
 
-	testList, fileList, tagList, _, _ := buildLists(pwData)
+	testList, fileList, tagList, _, _, _, _ := buildLists(pwData, nil)
 
 	if len(testList.Items()) != 2 {
 		t.Errorf("expected 2 test items, got %d", len(testList.Items()))
@@ -252,7 +256,7 @@ func TestBuildListsDescriptions(t *testing.T) {
 		},
 	}
 
-	_, fileList, tagList, tagToSpecs, fileToSpecs := buildLists(pw)
+	_, fileList, tagList, _, tagToSpecs, fileToSpecs, _ := buildLists(pw, nil)
 
 	assertDescription := func(items []list.Item, title string, want string) {
 		for _, it := range items {
@@ -276,3 +280,129 @@ func TestBuildListsDescriptions(t *testing.T) {
 		t.Errorf("expected 2 specs for file, got %d", len(fileToSpecs["example.test.js"]))
 	}
 }
+
+func TestBuildLists_Projects(t *testing.T) {
+	pw := PlaywrightJSON{
+		Suites: []Suite{
+			{
+				Title: "a.spec.ts",
+				File:  "a.spec.ts",
+				Specs: []Spec{
+					{
+						Title: "first",
+						File:  "a.spec.ts",
+						Line:  3,
+						Tests: []TestInstance{{ProjectName: "chromium"}, {ProjectName: "firefox"}},
+					},
+				},
+			},
+			{
+				Title: "b.spec.ts",
+				File:  "b.spec.ts",
+				Specs: []Spec{
+					{
+						Title: "second",
+						File:  "b.spec.ts",
+						Line:  7,
+						Tests: []TestInstance{{ProjectName: "chromium"}},
+					},
+				},
+			},
+		},
+	}
+
+	_, _, _, projectList, _, _, projectToSpecs := buildLists(pw, nil)
+
+	if projectList.Title != "Projects" {
+		t.Errorf("expected list title %q, got %q", "Projects", projectList.Title)
+	}
+	items := projectList.Items()
+	if len(items) != 2 {
+		t.Fatalf("expected 2 project items, got %d", len(items))
+	}
+	if got := items[0].(item); got.title != "chromium" || got.description != "2 tests across 2 files" {
+		t.Errorf("unexpected chromium item: %+v", got)
+	}
+	if got := items[1].(item); got.title != "firefox" || got.description != "1 test across 1 file" {
+		t.Errorf("unexpected firefox item: %+v", got)
+	}
+	if len(projectToSpecs["chromium"]) != 2 {
+		t.Errorf("expected 2 specs for chromium, got %d", len(projectToSpecs["chromium"]))
+	}
+}
+
+func TestBuildLists_SkipsUnnamedProject(t *testing.T) {
+	pw := PlaywrightJSON{
+		Suites: []Suite{{
+			File: "a.spec.ts",
+			Specs: []Spec{{
+				Title: "only",
+				File:  "a.spec.ts",
+				Line:  1,
+				Tests: []TestInstance{{ProjectName: ""}},
+			}},
+		}},
+	}
+
+	_, _, _, projectList, _, _, _ := buildLists(pw, nil)
+	if len(projectList.Items()) != 0 {
+		t.Errorf("expected no project items for the default project, got %d", len(projectList.Items()))
+	}
+}
+
+func TestBuildLists_Annotations(t *testing.T) {
+	pw := PlaywrightJSON{
+		Suites: []Suite{{
+			File: "flaky.spec.ts",
+			Specs: []Spec{
+				{
+					Title: "skipped one",
+					File:  "flaky.spec.ts",
+					Line:  4,
+					Tags:  []string{"@smoke"},
+					Tests: []TestInstance{{ProjectName: "chromium", Annotations: []Annotation{{Type: "slow"}, {Type: "skip"}}}},
+				},
+				{
+					Title: "fixme one",
+					File:  "flaky.spec.ts",
+					Line:  8,
+					Tags:  []string{"@smoke"},
+					Tests: []TestInstance{{ProjectName: "chromium", Annotations: []Annotation{{Type: "fixme"}}}},
+				},
+				{
+					Title: "expected failure",
+					File:  "flaky.spec.ts",
+					Line:  12,
+					Tags:  []string{"@smoke", "@broken"},
+					Tests: []TestInstance{{ProjectName: "chromium", Annotations: []Annotation{{Type: "fail"}}}},
+				},
+			},
+		}},
+	}
+
+	testList, fileList, tagList, projectList, _, _, _ := buildLists(pw, nil)
+
+	first := testList.Items()[0].(item)
+	if len(first.annotations) != 2 || first.annotations[0] != "skip" || first.annotations[1] != "slow" {
+		t.Errorf("expected annotations [skip slow], got %v", first.annotations)
+	}
+	if !first.skipped {
+		t.Errorf("expected skip-annotated test to be marked skipped")
+	}
+	if testList.Items()[2].(item).skipped {
+		t.Errorf("expected fail-annotated test to remain runnable")
+	}
+
+	if got := fileList.Items()[0].(item).description; got != "3 tests across 1 project (1 runnable)" {
+		t.Errorf("unexpected file description: %q", got)
+	}
+	for _, it := range tagList.Items() {
+		tag := it.(item)
+		if tag.title == "@broken" && (tag.skipped || tag.description != "1 test across 1 project") {
+			t.Errorf("unexpected @broken tag item: %+v", tag)
+		}
+	}
+	if got := projectList.Items()[0].(item).description; got != "3 tests across 1 file (1 runnable)" {
+		t.Errorf("unexpected project description: %q", got)
+	}
+}
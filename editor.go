@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorTemplates are the go-to-line arguments for common editors, keyed by
+// executable name. {file} and {line} are substituted; editors not listed here
+// are opened on the file alone.
+var editorTemplates = map[string][]string{
+	"vi":            {"+{line}", "{file}"},
+	"vim":           {"+{line}", "{file}"},
+	"nvim":          {"+{line}", "{file}"},
+	"nano":          {"+{line}", "{file}"},
+	"emacs":         {"+{line}", "{file}"},
+	"emacsclient":   {"+{line}", "{file}"},
+	"code":          {"--goto", "{file}:{line}"},
+	"code-insiders": {"--goto", "{file}:{line}"},
+	"codium":        {"--goto", "{file}:{line}"},
+	"cursor":        {"--goto", "{file}:{line}"},
+	"idea":          {"--line", "{line}", "{file}"},
+	"webstorm":      {"--line", "{line}", "{file}"},
+	"hx":            {"{file}:{line}"},
+	"helix":         {"{file}:{line}"},
+	"subl":          {"{file}:{line}"},
+	"zed":           {"{file}:{line}"},
+}
+
+type editorFinishedMsg struct {
+	path string
+	err  error
+}
+
+// editorArgv builds the command that opens path at line with the given
+// $VISUAL/$EDITOR value, which may include its own arguments.
+func editorArgv(editor, path string, line int) ([]string, error) {
+	words, err := splitCommandLine(editor)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("set $VISUAL or $EDITOR to open files")
+	}
+
+	template, ok := editorTemplates[filepath.Base(words[0])]
+	if !ok || line <= 0 {
+		return append(words, path), nil
+	}
+	for _, arg := range template {
+		arg = strings.ReplaceAll(arg, "{file}", path)
+		words = append(words, strings.ReplaceAll(arg, "{line}", strconv.Itoa(line)))
+	}
+	return words, nil
+}
+
+func currentEditor() string {
+	if editor := os.Getenv("VISUAL"); editor != "" {
+		return editor
+	}
+	return os.Getenv("EDITOR")
+}
+
+// openInEditor suspends the program and opens the highlighted item's source.
+func (m model) openInEditor() (tea.Model, tea.Cmd) {
+	selected := m.lists[m.focusedIdx].SelectedItem()
+	if selected == nil {
+		return m, nil
+	}
+	file, line, ok := previewTarget(selected.(item))
+	if !ok {
+		return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("No source file to open"))
+	}
+
+	path := resolveSpecPath(m.rootDir, file)
+	argv, err := editorArgv(currentEditor(), path, line)
+	if err != nil {
+		return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle(err.Error()))
+	}
+	cmd := exec.Command(argv[0], argv[1:]...)
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorFinishedMsg{path: path, err: err}
+	})
+}
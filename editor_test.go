@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEditorArgv(t *testing.T) {
+	tests := []struct {
+		editor string
+		line   int
+		want   string
+	}{
+		{"nvim", 12, "nvim +12 e2e/a.spec.ts"},
+		{"/usr/bin/vim", 12, "/usr/bin/vim +12 e2e/a.spec.ts"},
+		{"code -w", 12, "code -w --goto e2e/a.spec.ts:12"},
+		{"idea", 12, "idea --line 12 e2e/a.spec.ts"},
+		{"hx", 12, "hx e2e/a.spec.ts:12"},
+		{"emacsclient -t", 12, "emacsclient -t +12 e2e/a.spec.ts"},
+		{"gedit", 12, "gedit e2e/a.spec.ts"},
+		{"nvim", 0, "nvim e2e/a.spec.ts"},
+	}
+	for _, tt := range tests {
+		argv, err := editorArgv(tt.editor, "e2e/a.spec.ts", tt.line)
+		if err != nil {
+			t.Fatalf("editorArgv(%q) failed: %v", tt.editor, err)
+		}
+		if got := strings.Join(argv, " "); got != tt.want {
+			t.Errorf("editorArgv(%q, %d) = %q; want %q", tt.editor, tt.line, got, tt.want)
+		}
+	}
+
+	if _, err := editorArgv("", "e2e/a.spec.ts", 1); err == nil {
+		t.Errorf("expected an error when no editor is set")
+	}
+}
+
+func TestCurrentEditor_PrefersVisual(t *testing.T) {
+	t.Setenv("EDITOR", "vim")
+	t.Setenv("VISUAL", "code -w")
+	if got := currentEditor(); got != "code -w" {
+		t.Errorf("expected $VISUAL to win, got %q", got)
+	}
+	t.Setenv("VISUAL", "")
+	if got := currentEditor(); got != "vim" {
+		t.Errorf("expected $EDITOR fallback, got %q", got)
+	}
+}
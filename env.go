@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// envName is the env set from the config file to run tests with at startup.
+var envName string
+
+// envNames returns the env sets defined in the config file in name order.
+func envNames() []string {
+	names := make([]string, 0, len(userConfig.Envs))
+	for name := range userConfig.Envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// envVars returns the NAME=value pairs of an env set, sorted by name.
+func envVars(name string) []string {
+	set := userConfig.Envs[name]
+	vars := make([]string, 0, len(set))
+	for k, v := range set {
+		vars = append(vars, k+"="+v)
+	}
+	sort.Strings(vars)
+	return vars
+}
+
+// runCommand is playwrightCommand with the variables of an env set added on
+// top of the inherited environment.
+func runCommand(args, env []string) *exec.Cmd {
+	cmd := playwrightCommand(args)
+	if len(env) > 0 {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env, env...)
+	}
+	return cmd
+}
+
+// runCommandLine is the shell-quoted form of runCommand.
+func runCommandLine(args, env []string) string {
+	argv, runnerEnv := playwrightArgv(args)
+	return shellJoin(append(append(runnerEnv, env...), argv...))
+}
+
+// shownCommandLine is the command a run would use as shown in the options
+// panel and copied with y, with one line per config in monorepo mode.
+func (m model) shownCommandLine() (string, bool) {
+	_, env := m.runEnv()
+	if runs, ok := m.configRuns(); ok {
+		return configRunsCommandLine(runs, env), len(runs) > 0
+	}
+	args, ok := m.runArgs()
+	if !ok {
+		return "", false
+	}
+	return runCommandLine(args, env), true
+}
+
+// runEnv returns the env set a run should use. History replays keep the env
+// set they were recorded with, as it is now defined in the config file.
+func (m model) runEnv() (string, []string) {
+	if past, ok := m.highlightedHistory(); ok {
+		if past.Env == "" {
+			return "", nil
+		}
+		return past.Env, envVars(past.Env)
+	}
+	if m.env == "" {
+		return "", nil
+	}
+	return m.env, envVars(m.env)
+}
+
+// cycleEnv switches to the next env set, with no env set after the last one.
+func (m *model) cycleEnv() tea.Cmd {
+	names := envNames()
+	if len(names) == 0 {
+		return m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("No envs defined in the config file"))
+	}
+	next := names[0]
+	for i, name := range names {
+		if name == m.env {
+			next = ""
+			if i+1 < len(names) {
+				next = names[i+1]
+			}
+		}
+	}
+	m.env = next
+	if next == "" {
+		return m.lists[m.focusedIdx].NewStatusMessage(statusSelectStyle("Env: none"))
+	}
+	count := len(userConfig.Envs[next])
+	return m.lists[m.focusedIdx].NewStatusMessage(statusSelectStyle(fmt.Sprintf("Env: %s · %d variable%s", next, count, plural(count))))
+}
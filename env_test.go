@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func withEnvs(t *testing.T, envs map[string]map[string]string) {
+	t.Helper()
+	oldConfig, oldEnv := userConfig, envName
+	t.Cleanup(func() { userConfig, envName = oldConfig, oldEnv })
+	userConfig = pwgoConfig{Envs: envs}
+}
+
+func TestEnvVars_Sorted(t *testing.T) {
+	withEnvs(t, map[string]map[string]string{
+		"staging": {"TEST_ENV": "staging", "BASE_URL": "https://staging.example.com"},
+		"local":   {"BASE_URL": "http://localhost:3000"},
+	})
+	if got := envNames(); !reflect.DeepEqual(got, []string{"local", "staging"}) {
+		t.Errorf("envNames = %v", got)
+	}
+	want := []string{"BASE_URL=https://staging.example.com", "TEST_ENV=staging"}
+	if got := envVars("staging"); !reflect.DeepEqual(got, want) {
+		t.Errorf("envVars = %v; want %v", got, want)
+	}
+}
+
+func TestRunCommand_AddsEnv(t *testing.T) {
+	oldRunner := runnerCommand
+	t.Cleanup(func() { runnerCommand = oldRunner })
+	runnerCommand = "FORCE_COLOR=1 npx playwright"
+	cmd := runCommand([]string{"test"}, []string{"BASE_URL=http://localhost:3000"})
+	if got := cmd.Env[len(cmd.Env)-2:]; !reflect.DeepEqual(got, []string{"FORCE_COLOR=1", "BASE_URL=http://localhost:3000"}) {
+		t.Errorf("expected the env set after the runner's variables, got %v", got)
+	}
+	if got := runCommandLine([]string{"test"}, []string{"BASE_URL=http://localhost:3000"}); got != "FORCE_COLOR=1 BASE_URL=http://localhost:3000 npx playwright test" {
+		t.Errorf("runCommandLine = %q", got)
+	}
+	runnerCommand = "npx playwright"
+	if cmd := runCommand([]string{"test"}, nil); cmd.Env != nil {
+		t.Errorf("expected the inherited environment without an env set")
+	}
+}
+
+func TestCycleEnv(t *testing.T) {
+	isolateUserDirs(t)
+	withEnvs(t, map[string]map[string]string{
+		"local":   {"BASE_URL": "http://localhost:3000"},
+		"staging": {"BASE_URL": "https://staging.example.com"},
+	})
+	m := NewModel(presetTestData(), nil, nil)
+
+	var seen []string
+	for i := 0; i < 3; i++ {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}})
+		m = updated.(model)
+		seen = append(seen, m.env)
+	}
+	if !reflect.DeepEqual(seen, []string{"local", "staging", ""}) {
+		t.Errorf("expected to cycle through the envs and back to none, got %q", seen)
+	}
+
+	// Runs record the env they used
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}})
+	m = updated.(model)
+	m.lists[m.listIdx("Tests")].Select(0)
+	args, _ := m.runArgs()
+	entry := m.newHistoryEntry(args)
+	if entry.Env != "local" || !reflect.DeepEqual(entry.EnvVars, []string{"BASE_URL=http://localhost:3000"}) {
+		t.Errorf("expected the env in the history entry, got %q %v", entry.Env, entry.EnvVars)
+	}
+	if !strings.Contains(m.View(), "Env: ") {
+		t.Errorf("expected the env to be shown above the lists")
+	}
+}
+
+func TestParseArgs_UnknownEnv(t *testing.T) {
+	withEnvs(t, nil)
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ".pwgo.yaml"), []byte(`
+envs:
+  staging:
+    BASE_URL: https://staging.example.com
+env: staging
+`), 0o644)
+	chdir(t, dir)
+	oldRunner, oldConfigPath, oldOpts := runnerCommand, configPath, currentListOptions
+	t.Cleanup(func() { runnerCommand, configPath, currentListOptions = oldRunner, oldConfigPath, oldOpts })
+
+	if _, _, err := parseArgs(nil); err != nil || envName != "staging" {
+		t.Errorf("expected the config's default env, got %q, %v", envName, err)
+	}
+	envName = ""
+	if _, _, err := parseArgs([]string{"--env", "prod"}); err == nil || !strings.Contains(err.Error(), `"prod"`) {
+		t.Errorf("expected an error for an unknown env, got %v", err)
+	}
+}
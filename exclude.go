@@ -0,0 +1,186 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var excludedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Strikethrough(true)
+
+// toggleExclude adds the highlighted item to the Selected list as an
+// exclusion, or flips an item already in the Selected list between included
+// and excluded.
+func (m model) toggleExclude() (tea.Model, tea.Cmd) {
+	selectedItem := m.lists[m.focusedIdx].SelectedItem()
+	if selectedItem == nil {
+		return m, nil
+	}
+
+	if m.focusedIdx == m.selectedIdx() {
+		it := selectedItem.(item)
+		if it.stale {
+			return m, nil
+		}
+		it.excluded = !it.excluded
+		m.lists[m.focusedIdx].SetItem(m.lists[m.focusedIdx].Index(), it)
+		status := "Included " + sourceNoun(it.source)
+		if it.excluded {
+			status = "Excluded " + sourceNoun(it.source)
+		}
+		return m, m.lists[m.focusedIdx].NewStatusMessage(statusSelectStyle(status))
+	}
+
+	it := selectedItem.(item)
+	if it.source == "Presets" {
+		return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("Presets cannot be excluded"))
+	}
+	if it.source == "History" {
+		return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("Past runs cannot be excluded"))
+	}
+	if it.node != nil {
+		it = it.node.selectionItem()
+	}
+	it.excluded = true
+	m.lists[m.selectedIdx()].InsertItem(len(m.lists[m.selectedIdx()].Items()), it)
+	m.lists[m.focusedIdx].ResetFilter()
+	m.refreshLists()
+	return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("Excluded " + sourceNoun(it.source)))
+}
+
+// itemLocations expands an item to the file:line of every test it covers.
+func (m model) itemLocations(it item) []string {
+	var specs []item
+	switch it.source {
+	case "Tests":
+		return []string{it.description}
+	case "Files":
+		specs = m.fileToSpecs[it.title]
+	case "Tags":
+		specs = m.tagToSpecs[it.title]
+	case "Suites", "Queries":
+		specs = it.specs
+	}
+	locations := make([]string, len(specs))
+	for i, s := range specs {
+		locations[i] = s.description
+	}
+	return locations
+}
+
+// selectionLocations resolves the selection to file:line locations with
+// excluded tests taken out. ok is false when no tests are excluded, in which
+// case the usual per-item arguments apply. With only exclusions selected the
+// starting point is every test.
+func (m model) selectionLocations(items []item) (locations []string, ok bool) {
+	excluded := map[string]struct{}{}
+	included := false
+	for _, it := range items {
+		if it.stale || it.source == "Projects" {
+			continue
+		}
+		if it.excluded {
+			for _, loc := range m.itemLocations(it) {
+				excluded[loc] = struct{}{}
+			}
+		} else {
+			included = true
+		}
+	}
+	if len(excluded) == 0 {
+		return nil, false
+	}
+
+	var candidates []string
+	if included {
+		for _, it := range items {
+			if !it.stale && !it.excluded && it.source != "Projects" {
+				candidates = append(candidates, m.itemLocations(it)...)
+			}
+		}
+	} else {
+		for _, it := range m.originalTests {
+			candidates = append(candidates, it.description)
+		}
+	}
+
+	seen := map[string]struct{}{}
+	for _, loc := range candidates {
+		if _, ok := excluded[loc]; ok {
+			continue
+		}
+		if _, ok := seen[loc]; ok {
+			continue
+		}
+		seen[loc] = struct{}{}
+		locations = append(locations, loc)
+	}
+	return locations, true
+}
+
+// runProjects returns the --project values for the selection: selected
+// projects, else the CLI projects, minus any excluded ones. Excluding a
+// project with none chosen starts from every project.
+func (m model) runProjects(items []item) []string {
+	var included []string
+	excluded := map[string]struct{}{}
+	for _, it := range items {
+		if it.source != "Projects" || it.stale {
+			continue
+		}
+		if it.excluded {
+			excluded[it.title] = struct{}{}
+		} else {
+			included = append(included, it.title)
+		}
+	}
+
+	if len(included) == 0 {
+		included = m.projects
+	}
+	if len(excluded) == 0 {
+		return included
+	}
+	if len(included) == 0 {
+		for _, p := range m.originalProjects {
+			included = append(included, p.title)
+		}
+	}
+
+	var projects []string
+	for _, p := range included {
+		if _, ok := excluded[p]; !ok {
+			projects = append(projects, p)
+		}
+	}
+	return projects
+}
+
+// nothingToRun tells the user a run was refused because leavesNothingToRun.
+func (m model) nothingToRun() tea.Cmd {
+	return m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("Nothing left to run: every item is excluded or stale"))
+}
+
+// leavesNothingToRun reports whether exclusions remove every test or project,
+// or every item has gone stale. Without the stale check the run would fall
+// back to the whole suite.
+func (m model) leavesNothingToRun(items []item) bool {
+	live := false
+	for _, it := range items {
+		if !it.stale {
+			live = true
+			break
+		}
+	}
+	if !live {
+		return true
+	}
+	if locations, ok := m.selectionLocations(items); ok && len(locations) == 0 {
+		return true
+	}
+	for _, it := range items {
+		if it.source == "Projects" && it.excluded && !it.stale {
+			return len(m.runProjects(items)) == 0
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func exclusionModel() model {
+	specs := []item{
+		{source: "Tests", title: "one", description: "a.spec.ts:1", tags: []string{"@smoke"}},
+		{source: "Tests", title: "two", description: "a.spec.ts:5"},
+		{source: "Tests", title: "three", description: "a.spec.ts:9", tags: []string{"@smoke"}},
+		{source: "Tests", title: "four", description: "b.spec.ts:2", tags: []string{"@smoke"}},
+	}
+	return model{
+		originalTests:    specs,
+		originalProjects: []item{{source: "Projects", title: "chromium"}, {source: "Projects", title: "firefox"}, {source: "Projects", title: "webkit"}},
+		fileToSpecs:      map[string][]item{"a.spec.ts": specs[:3], "b.spec.ts": specs[3:]},
+		tagToSpecs:       map[string][]item{"@smoke": {specs[0], specs[2], specs[3]}},
+		projects:         []string{},
+	}
+}
+
+func TestBuildArgs_Exclusions(t *testing.T) {
+	m := exclusionModel()
+
+	args := m.buildArgs([]item{
+		{source: "Files", title: "a.spec.ts"},
+		{source: "Tests", description: "a.spec.ts:1", excluded: true},
+		{source: "Tests", description: "a.spec.ts:9", excluded: true},
+	})
+	if got := strings.Join(args, " "); got != "test a.spec.ts:5" {
+		t.Errorf("file minus two tests: got %q", got)
+	}
+
+	args = m.buildArgs([]item{
+		{source: "Tags", title: "@smoke"},
+		{source: "Projects", title: "firefox", excluded: true},
+	})
+	if got := strings.Join(args, " "); got != "test a.spec.ts:1 a.spec.ts:9 b.spec.ts:2 --project chromium --project webkit" {
+		t.Errorf("tag minus a project: got %q", got)
+	}
+
+	args = m.buildArgs([]item{{source: "Files", title: "a.spec.ts", excluded: true}})
+	if got := strings.Join(args, " "); got != "test b.spec.ts:2" {
+		t.Errorf("exclusions alone should start from every test: got %q", got)
+	}
+}
+
+func TestLeavesNothingToRun(t *testing.T) {
+	m := exclusionModel()
+	if m.leavesNothingToRun([]item{{source: "Tags", title: "@smoke"}}) {
+		t.Errorf("a plain selection should be runnable")
+	}
+	if !m.leavesNothingToRun([]item{
+		{source: "Files", title: "b.spec.ts"},
+		{source: "Tags", title: "@smoke", excluded: true},
+	}) {
+		t.Errorf("expected excluding every selected test to leave nothing to run")
+	}
+
+	m.projects = []string{"chromium"}
+	if !m.leavesNothingToRun([]item{{source: "Projects", title: "chromium", excluded: true}}) {
+		t.Errorf("expected excluding the only project to leave nothing to run")
+	}
+
+	m.projects = nil
+	if !m.leavesNothingToRun([]item{{source: "Tests", title: "gone", stale: true}, {source: "Files", title: "old.spec.ts", stale: true}}) {
+		t.Errorf("expected a selection of stale items to leave nothing to run")
+	}
+	if m.leavesNothingToRun([]item{{source: "Tests", title: "gone", stale: true}, {source: "Projects", title: "chromium"}}) {
+		t.Errorf("expected a live project to still be runnable")
+	}
+}
+
+func TestToggleExclude(t *testing.T) {
+	isolateUserDirs(t)
+	m := NewModel(presetTestData(), nil, nil)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = updated.(model)
+
+	selected := m.lists[m.selectedIdx()].Items()
+	if len(selected) != 1 || !selected[0].(item).excluded {
+		t.Fatalf("expected an excluded item in the Selected list, got %+v", selected)
+	}
+	if len(m.lists[m.listIdx("Tests")].Items()) != 1 {
+		t.Errorf("expected the excluded test to leave the Tests list")
+	}
+
+	m.focusedIdx, m.rightFocused = m.selectedIdx(), true
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = updated.(model)
+	if m.lists[m.selectedIdx()].Items()[0].(item).excluded {
+		t.Errorf("expected x in the Selected list to include the item again")
+	}
+
+	s := newSavedSelection([]item{
+		{source: "Files", title: "cart.spec.ts"},
+		{source: "Tests", description: "cart.spec.ts:3", excluded: true},
+	}, nil)
+	if s.Exclude == nil || len(s.Exclude.Tests) != 1 || s.summary() != "1 file · excluding 1 test" {
+		t.Errorf("expected exclusions in the saved selection, got %+v", s)
+	}
+	items, stale := m.resolve(s)
+	if stale != 0 || len(items) != 2 || !items[1].excluded {
+		t.Errorf("expected the exclusion to resolve as excluded, got %+v", items)
+	}
+}
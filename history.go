@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// historyLimit is how many past runs the History list shows.
+const historyLimit = 100
+
+// historyEntry records one Playwright run launched from pwgo.
+type historyEntry struct {
+	Time      time.Time      `json:"time"`
+	Dir       string         `json:"dir"`
+	Args      []string       `json:"args"`
+	Selection savedSelection `json:"selection"`
+	Projects  []string       `json:"projects,omitempty"`
+	Env       string         `json:"env,omitempty"`
+	// EnvVars are not saved, as env sets can hold secrets; replays take them
+	// from the config file by the name in Env
+	EnvVars []string `json:"-"`
+	// Runs are the invocations of a run across several configs
+	Runs       []configRun `json:"runs,omitempty"`
+	ExitCode   int         `json:"exitCode"`
+	DurationMs int64       `json:"durationMs"`
+}
+
+type execFinishedMsg struct {
+	entry historyEntry
+	err   error
+}
+
+// historyPath is a single JSON Lines file shared by every project; entries
+// carry the directory they were run from.
+func historyPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pwgo", "history.jsonl")
+}
+
+// loadHistory returns the runs made from dir, newest first.
+func loadHistory(path, dir string) ([]historyEntry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []historyEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry historyEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Dir != dir {
+			// Skip lines from other projects and any that were cut short
+			continue
+		}
+		entries = append([]historyEntry{entry}, entries...)
+	}
+	if len(entries) > historyLimit {
+		entries = entries[:historyLimit]
+	}
+	return entries, scanner.Err()
+}
+
+func appendHistory(path string, entry historyEntry) error {
+	if path == "" {
+		return fmt.Errorf("no config directory for run history")
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// exitCode turns the error from a finished command into its exit status,
+// using -1 when the command could not be run at all.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+func (e historyEntry) key() string {
+	return e.Time.Format(time.RFC3339Nano)
+}
+
+func (e historyEntry) status() string {
+	duration := (time.Duration(e.DurationMs) * time.Millisecond).Round(100 * time.Millisecond)
+	if e.ExitCode == 0 {
+		return statusSelectStyle(fmt.Sprintf("passed in %s", duration))
+	}
+	return statusRemoveStyle(fmt.Sprintf("exit %d after %s", e.ExitCode, duration))
+}
+
+func (e historyEntry) label() string {
+	label := e.Time.Local().Format("Jan 2 15:04:05") + "  " + e.status()
+	if e.Env != "" {
+		label += "  env " + e.Env
+	}
+	return label
+}
+
+func newHistoryItem(e historyEntry) item {
+	return item{
+		title:       e.key(),
+		label:       e.label(),
+		description: e.Selection.summary(),
+		source:      "History",
+		filter:      e.filterValue(),
+	}
+}
+
+// filterValue lets the History list be filtered by what a run covered and
+// the arguments it was run with, rather than by its timestamp.
+func (e historyEntry) filterValue() string {
+	parts := append([]string{}, e.Args...)
+	for _, r := range e.Runs {
+		parts = append(parts, r.Args...)
+	}
+	s := e.Selection
+	for _, values := range [][]string{s.Tests, s.Suites, s.Files, s.Tags, s.Projects, s.Queries, s.Args} {
+		parts = append(parts, values...)
+	}
+	if e.Env != "" {
+		parts = append(parts, e.Env)
+	}
+	return strings.Join(parts, " ")
+}
+
+func newHistoryList(entries []historyEntry) list.Model {
+	items := make([]list.Item, len(entries))
+	for i, e := range entries {
+		items[i] = newHistoryItem(e)
+	}
+
+	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "History"
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keyMap.Replay, keyMap.Load}
+	}
+	l.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keyMap.Replay, keyMap.Run, keyMap.Load, keyMap.ToggleLeft, keyMap.ToggleRight, keyMap.Copy}
+	}
+	return l
+}
+
+// highlightedHistory returns the past run highlighted on the History list.
+func (m model) highlightedHistory() (historyEntry, bool) {
+	if m.lists[m.focusedIdx].Title != "History" {
+		return historyEntry{}, false
+	}
+	selected := m.lists[m.focusedIdx].SelectedItem()
+	if selected == nil {
+		return historyEntry{}, false
+	}
+	for _, e := range m.history {
+		if e.key() == selected.(item).title {
+			return e, true
+		}
+	}
+	return historyEntry{}, false
+}
+
+// newHistoryEntry describes a run of args that is about to start. Replays
+// keep the selection of the run they repeat.
+func (m model) newHistoryEntry(args []string) historyEntry {
+	cwd, _ := os.Getwd()
+	entry := historyEntry{Time: time.Now(), Dir: cwd, Args: args}
+	entry.Env, entry.EnvVars = m.runEnv()
+	if past, ok := m.highlightedHistory(); ok {
+		entry.Selection, entry.Projects = past.Selection, past.Projects
+		return entry
+	}
+	items, extraArgs := m.runItems()
+	entry.Selection = newSavedSelection(items, extraArgs)
+	entry.Projects = m.runProjects(items)
+	return entry
+}
+
+// rerunHistoryEntry describes a re-run of failures from the results screen.
+func (m model) rerunHistoryEntry(items []item, args []string) historyEntry {
+	cwd, _ := os.Getwd()
+	env, envVars := m.runEnv()
+	return historyEntry{
+		Time:      time.Now(),
+		Dir:       cwd,
+		Args:      args,
+		Selection: newSavedSelection(items, m.extraArgs),
+		Projects:  m.runProjects(items),
+		Env:       env,
+		EnvVars:   envVars,
+	}
+}
+
+// recordRun finishes an entry with the run's outcome, saves it and adds it
+// to the top of the History list.
+func (m *model) recordRun(entry historyEntry, err error) error {
+	entry.ExitCode = exitCode(err)
+	entry.DurationMs = time.Since(entry.Time).Milliseconds()
+	m.lastRun = &entry
+	m.history = append([]historyEntry{entry}, m.history...)
+	if len(m.history) > historyLimit {
+		m.history = m.history[:historyLimit]
+	}
+	m.lists[m.listIdx("History")].SetItems(newHistoryList(m.history).Items())
+	return appendHistory(historyPath(), entry)
+}
+
+// handleExecFinished records a run made with the terminal handed over to
+// Playwright, then exits or, with --stay, returns to the picker.
+func (m model) handleExecFinished(msg execFinishedMsg) (tea.Model, tea.Cmd) {
+	err := m.recordRun(msg.entry, msg.err)
+	if !stayAfterRun {
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error saving run history:", err)
+		}
+		return m, tea.Quit
+	}
+
+	m.quitting = false
+	if err != nil {
+		return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("Error saving run history: " + err.Error()))
+	}
+	return m, nil
+}
+
+// loadHistorySelection adds a past run's selection to the Selected list.
+func (m *model) loadHistorySelection(e historyEntry) string {
+	stale := m.loadSelection(e.Selection)
+	when := e.Time.Local().Format("Jan 2 15:04:05")
+	if stale == 1 {
+		return statusRemoveStyle(fmt.Sprintf("Loaded run from %s with 1 stale entry", when))
+	}
+	if stale > 1 {
+		return statusRemoveStyle(fmt.Sprintf("Loaded run from %s with %d stale entries", when, stale))
+	}
+	return statusSelectStyle("Loaded run from " + when)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHistory_AppendAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pwgo", "history.jsonl")
+	first := historyEntry{Time: time.Unix(100, 0), Dir: "/repo", Args: []string{"test", "cart.spec.ts:3"}}
+	other := historyEntry{Time: time.Unix(200, 0), Dir: "/elsewhere", Args: []string{"test"}}
+	second := historyEntry{Time: time.Unix(300, 0), Dir: "/repo", Args: []string{"test", "--project", "chromium"}, ExitCode: 1}
+	for _, e := range []historyEntry{first, other, second} {
+		if err := appendHistory(path, e); err != nil {
+			t.Fatalf("appendHistory failed: %v", err)
+		}
+	}
+	// A line cut short by a crash does not hide the rest
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	f.WriteString("{\"time\":\n")
+	f.Close()
+
+	entries, err := loadHistory(path, "/repo")
+	if err != nil {
+		t.Fatalf("loadHistory failed: %v", err)
+	}
+	if len(entries) != 2 || !reflect.DeepEqual(entries[0].Args, second.Args) || !reflect.DeepEqual(entries[1].Args, first.Args) {
+		t.Errorf("expected this project's runs newest first, got %+v", entries)
+	}
+
+	if entries, err := loadHistory(filepath.Join(t.TempDir(), "missing.jsonl"), "/repo"); err != nil || entries != nil {
+		t.Errorf("expected no history and no error for a missing file, got %v, %v", entries, err)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	if got := exitCode(nil); got != 0 {
+		t.Errorf("exitCode(nil) = %d; want 0", got)
+	}
+	if got := exitCode(exec.Command("sh", "-c", "exit 3").Run()); got != 3 {
+		t.Errorf("exitCode(exit 3) = %d; want 3", got)
+	}
+	if got := exitCode(exec.Command("pwgo-does-not-exist").Run()); got != -1 {
+		t.Errorf("exitCode(missing command) = %d; want -1", got)
+	}
+}
+
+func TestHistory_RecordReplayAndLoad(t *testing.T) {
+	isolateUserDirs(t)
+	m := NewModel(presetTestData(), nil, nil)
+
+	// Run the highlighted test and record it as it would be on exit
+	m.lists[m.listIdx("Tests")].Select(0)
+	args, _ := m.runArgs()
+	entry := m.newHistoryEntry(args)
+	if !reflect.DeepEqual(entry.Selection.Tests, []string{"cart.spec.ts:3"}) {
+		t.Fatalf("expected the run's selection to be recorded, got %+v", entry.Selection)
+	}
+	updated, cmd := m.Update(execFinishedMsg{entry: entry})
+	m = updated.(model)
+	if cmd == nil {
+		t.Errorf("expected pwgo to exit after the run")
+	}
+	cwd, _ := os.Getwd()
+	if saved, _ := loadHistory(historyPath(), cwd); len(saved) != 1 {
+		t.Fatalf("expected the run to be saved, got %d entries", len(saved))
+	}
+
+	m.focusedIdx = m.listIdx("History")
+	if len(m.lists[m.focusedIdx].Items()) != 1 {
+		t.Fatalf("expected the run on the History list")
+	}
+	// Replays use the recorded arguments even with other items selected
+	m.lists[m.selectedIdx()].InsertItem(0, m.originalTests[1])
+	if replay, ok := m.runArgs(); !ok || !reflect.DeepEqual(replay, args) {
+		t.Errorf("runArgs on History = %v; want %v", replay, args)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = updated.(model)
+	if got := len(m.lists[m.selectedIdx()].Items()); got != 2 {
+		t.Errorf("expected the past selection to be added to Selected, got %d items", got)
+	}
+}
+
+func TestHandleExecFinished_StaysInPicker(t *testing.T) {
+	isolateUserDirs(t)
+	oldStay := stayAfterRun
+	defer func() { stayAfterRun = oldStay }()
+	stayAfterRun = true
+
+	m := NewModel(presetTestData(), nil, nil)
+	m.lists[m.selectedIdx()].InsertItem(0, m.originalTests[0])
+	m.quitting = true
+	err := exec.Command("sh", "-c", "exit 1").Run()
+
+	updated, cmd := m.Update(execFinishedMsg{entry: m.newHistoryEntry([]string{"test", "cart.spec.ts:3"}), err: err})
+	m = updated.(model)
+	if m.quitting || cmd != nil {
+		t.Fatalf("expected to return to the picker")
+	}
+	if len(m.lists[m.selectedIdx()].Items()) != 1 {
+		t.Errorf("expected the selection to be kept")
+	}
+	if m.lastRun == nil || m.lastRun.ExitCode != 1 {
+		t.Errorf("expected the exit code to be kept for the status bar, got %+v", m.lastRun)
+	}
+}
+
+func TestHistory_EnvValuesNotSaved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	entry := historyEntry{Time: time.Unix(100, 0), Dir: "/repo", Args: []string{"test"}, Env: "staging", EnvVars: []string{"API_TOKEN=hunter2"}}
+	if err := appendHistory(path, entry); err != nil {
+		t.Fatalf("appendHistory failed: %v", err)
+	}
+	raw, _ := os.ReadFile(path)
+	if strings.Contains(string(raw), "hunter2") || !strings.Contains(string(raw), "staging") {
+		t.Errorf("expected only the env set name in the history file, got %s", raw)
+	}
+}
+
+func TestHistory_FilterAndReplayChecks(t *testing.T) {
+	isolateUserDirs(t)
+	m := NewModel(presetTestData(), nil, nil)
+	entry := historyEntry{
+		Time:      time.Unix(100, 0),
+		Args:      []string{"test", "--ui", "cart.spec.ts:3"},
+		Selection: savedSelection{Tests: []string{"cart.spec.ts:3"}},
+	}
+	if filter := newHistoryItem(entry).FilterValue(); !strings.Contains(filter, "cart.spec.ts:3") || !strings.Contains(filter, "--ui") {
+		t.Errorf("expected History items to filter on their command, got %q", filter)
+	}
+
+	m.history = []historyEntry{entry}
+	m.focusedIdx = m.listIdx("History")
+	m.lists[m.focusedIdx].SetItems(newHistoryList(m.history).Items())
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updated.(model)
+	if m.runEntry.Args != nil {
+		t.Errorf("expected a replay with --ui to be refused inside pwgo")
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+var listKinds = []string{"tests", "files", "tags", "projects"}
+
+var listFormats = []string{"plain", "json", "tsv"}
+
+// listEntry is one row of `pwgo list` output. Location is set for tests and
+// Summary holds the "N tests across M projects" helper for everything else.
+// Estimate depends on the runs made on this machine, so it is only in JSON.
+type listEntry struct {
+	Title       string   `json:"title"`
+	Location    string   `json:"location,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Estimate    string   `json:"estimate,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Annotations []string `json:"annotations,omitempty"`
+	Skipped     bool     `json:"skipped"`
+}
+
+// runList implements `pwgo list <kind>`: it loads the same data as the TUI and
+// prints one list without starting Bubble Tea.
+func runList(args []string, w io.Writer) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("usage: pwgo list %s [--format %s] [options]", strings.Join(listKinds, "|"), strings.Join(listFormats, "|"))
+	}
+	kind := args[0]
+	if !containsString(listKinds, kind) {
+		return fmt.Errorf("unknown list %q, expected one of %s", kind, strings.Join(listKinds, ", "))
+	}
+
+	format := "plain"
+	var rest []string
+	for i := 1; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--format":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--format="):
+			format = strings.TrimPrefix(arg, "--format=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if !containsString(listFormats, format) {
+		return fmt.Errorf("unknown format %q, expected one of %s", format, strings.Join(listFormats, ", "))
+	}
+
+	pwData, _, _, err := prepareDataFrom(rest)
+	if err != nil {
+		return err
+	}
+	return writeList(w, listEntries(pwData, kind, readTimings()), format)
+}
+
+// listEntries builds the entries for one list from the same aggregation the
+// TUI uses. Tests keep their --list order; everything else is sorted by title.
+func listEntries(pwData PlaywrightJSON, kind string, timings testTimings) []listEntry {
+	testList, fileList, tagList, projectList, _, _, _ := buildLists(pwData, timings)
+
+	var entries []listEntry
+	switch kind {
+	case "tests":
+		for _, li := range testList.Items() {
+			it := li.(item)
+			entries = append(entries, listEntry{
+				Title:       it.title,
+				Location:    it.description,
+				Tags:        it.tags,
+				Annotations: it.annotations,
+				Estimate:    it.estimate.String(),
+				Skipped:     it.skipped,
+			})
+		}
+		return entries
+	case "files":
+		entries = summaryEntries(fileList.Items())
+	case "tags":
+		entries = summaryEntries(tagList.Items())
+	case "projects":
+		entries = summaryEntries(projectList.Items())
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Title < entries[j].Title })
+	return entries
+}
+
+func summaryEntries(items []list.Item) []listEntry {
+	entries := make([]listEntry, 0, len(items))
+	for _, li := range items {
+		it := li.(item)
+		entries = append(entries, listEntry{
+			Title:    it.title,
+			Summary:  it.description,
+			Estimate: it.estimate.String(),
+			Tags:     it.tags,
+			Skipped:  it.skipped,
+		})
+	}
+	return entries
+}
+
+// detail is the second column of plain and TSV output.
+func (e listEntry) detail() string {
+	if e.Location != "" {
+		return e.Location
+	}
+	return e.Summary
+}
+
+func writeList(w io.Writer, entries []listEntry, format string) error {
+	switch format {
+	case "json":
+		if entries == nil {
+			entries = []listEntry{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case "tsv":
+		for _, e := range entries {
+			fields := []string{e.Title, e.detail(), strings.Join(e.Tags, ","), strings.Join(e.Annotations, ",")}
+			for i, f := range fields {
+				fields[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(f)
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, e := range entries {
+			var extra []string
+			extra = append(extra, e.Tags...)
+			for _, a := range e.Annotations {
+				extra = append(extra, "["+a+"]")
+			}
+			if len(extra) == 0 {
+				fmt.Fprintf(tw, "%s\t%s\n", e.Title, e.detail())
+			} else {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", e.Title, e.detail(), strings.Join(extra, " "))
+			}
+		}
+		return tw.Flush()
+	}
+}
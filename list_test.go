@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func listTestData() PlaywrightJSON {
+	return PlaywrightJSON{
+		Suites: []Suite{{
+			Title: "b.spec.ts",
+			File:  "b.spec.ts",
+			Specs: []Spec{
+				{Title: "first", File: "b.spec.ts", Line: 3, Tags: []string{"@smoke"}, Tests: []TestInstance{{ProjectName: "chromium"}, {ProjectName: "webkit"}}},
+				{Title: "second", File: "b.spec.ts", Line: 9, Tests: []TestInstance{
+					{ProjectName: "chromium", Annotations: []Annotation{{Type: "skip"}}},
+					{ProjectName: "webkit", Annotations: []Annotation{{Type: "skip"}}},
+				}},
+			},
+		}, {
+			Title: "a.spec.ts",
+			File:  "a.spec.ts",
+			Specs: []Spec{
+				{Title: "third", File: "a.spec.ts", Line: 1, Tags: []string{"@slow", "@smoke"}, Tests: []TestInstance{{ProjectName: "chromium"}}},
+			},
+		}},
+	}
+}
+
+func TestListEntries(t *testing.T) {
+	data := listTestData()
+
+	tests := listEntries(data, "tests", nil)
+	if len(tests) != 3 || tests[0].Location != "b.spec.ts:3" || tests[2].Location != "a.spec.ts:1" {
+		t.Fatalf("expected tests in --list order, got %+v", tests)
+	}
+	if !tests[1].Skipped || len(tests[1].Annotations) != 1 || tests[1].Annotations[0] != "skip" {
+		t.Errorf("expected the skipped annotation on the second test, got %+v", tests[1])
+	}
+
+	files := listEntries(data, "files", nil)
+	if len(files) != 2 || files[0].Title != "a.spec.ts" {
+		t.Fatalf("expected files sorted by title, got %+v", files)
+	}
+	if files[1].Summary != "4 tests across 2 projects (2 runnable)" {
+		t.Errorf("unexpected file summary: %q", files[1].Summary)
+	}
+
+	tags := listEntries(data, "tags", nil)
+	if len(tags) != 2 || tags[0].Title != "@slow" || tags[1].Summary != "4 tests across 2 projects" {
+		t.Errorf("unexpected tag entries: %+v", tags)
+	}
+
+	// Estimates stay out of the summary, which only counts tests
+	timings := testTimings{timingKey("a.spec.ts", "third"): {"chromium": 1500}}
+	files = listEntries(data, "files", timings)
+	if files[0].Summary != "1 test across 1 project" || files[0].Estimate != "~1.5s" {
+		t.Errorf("expected the estimate apart from the summary, got %+v", files[0])
+	}
+
+	projects := listEntries(data, "projects", nil)
+	if len(projects) != 2 || projects[0].Title != "chromium" || projects[0].Summary != "3 tests across 2 files (2 runnable)" {
+		t.Errorf("unexpected project entries: %+v", projects)
+	}
+}
+
+func TestWriteList_Formats(t *testing.T) {
+	entries := []listEntry{
+		{Title: "Cart › adds", Location: "cart.spec.ts:3", Tags: []string{"@smoke"}},
+		{Title: "Cart › removes", Location: "cart.spec.ts:8", Annotations: []string{"skip"}, Skipped: true},
+	}
+
+	var buf bytes.Buffer
+	if err := writeList(&buf, entries, "tsv"); err != nil {
+		t.Fatal(err)
+	}
+	want := "Cart › adds\tcart.spec.ts:3\t@smoke\t\nCart › removes\tcart.spec.ts:8\t\tskip\n"
+	if buf.String() != want {
+		t.Errorf("unexpected TSV output:\n%q\nwant:\n%q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := writeList(&buf, entries, "plain"); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "@smoke") || !strings.HasSuffix(lines[1], "[skip]") {
+		t.Errorf("unexpected plain output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeList(&buf, nil, "json"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("expected an empty JSON array, got %q", buf.String())
+	}
+}
+
+func TestRunList(t *testing.T) {
+	isolateUserDirs(t)
+	chdir(t, t.TempDir())
+	jsonPath := writeTempJSON(t, listTestData())
+	defer os.Remove(jsonPath)
+
+	var buf bytes.Buffer
+	if err := runList([]string{"tags", "--format=json", "--json-data-path", jsonPath}, &buf); err != nil {
+		t.Fatalf("runList failed: %v", err)
+	}
+	var entries []listEntry
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, buf.String())
+	}
+	if len(entries) != 2 || entries[1].Title != "@smoke" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+
+	for _, args := range [][]string{nil, {"suites"}, {"tests", "--format", "xml"}} {
+		if err := runList(args, &buf); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var (
+	spinnerStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	loadErrorStyle  = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("9")).Padding(0, 1)
+	loadErrorHeader = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)
+)
+
+// NewLoadingModel starts with empty lists and lists the tests in the
+// background, so the TUI appears before Playwright has finished.
+func NewLoadingModel(projects []string, extraArgs []string) model {
+	m := newModel(PlaywrightJSON{}, projects, extraArgs)
+	m.loading = true
+	m.reloading = true
+	return m
+}
+
+// startReload lists the tests again in the background, or queues another
+// listing if one is already running.
+func (m *model) startReload() tea.Cmd {
+	if m.reloading {
+		m.reloadPending = true
+		return nil
+	}
+	m.reloading = true
+	return tea.Batch(reloadData(), m.spinner.Tick)
+}
+
+// dataLoaded applies a successful listing. The first one also selects the
+// --preset and starts watching for changes.
+func (m *model) dataLoaded(pwData PlaywrightJSON) tea.Cmd {
+	stale := m.applyData(pwData)
+	// Spec files may have changed too, so compare them again
+	changes := m.loadChanges()
+	if m.loading {
+		m.loading = false
+		cmds := []tea.Cmd{changes}
+		if watchFiles && m.watcher == nil {
+			m.watcher = startWatcher(watchTarget(m.rootDir))
+			cmds = append(cmds, waitForChange(m.watcher.changes))
+		}
+		return tea.Batch(append(cmds, m.loadStartupPreset())...)
+	}
+
+	status := fmt.Sprintf("Refreshed · %d test%s", len(m.originalTests), plural(len(m.originalTests)))
+	if stale > 0 {
+		status += fmt.Sprintf(" · %d selected item%s not found", stale, plural(stale))
+		return tea.Batch(changes, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle(status)))
+	}
+	return tea.Batch(changes, m.lists[m.focusedIdx].NewStatusMessage(statusSelectStyle(status)))
+}
+
+// updateLoading handles keys while the tests are first being listed or the
+// error panel is showing.
+func (m model) updateLoading(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "ctrl+r":
+		return m, m.startReload()
+	case "ctrl+g":
+		return m, m.openPrompt(promptGrep, "--grep:", currentListOptions.grep)
+	case "esc":
+		// Without any data there is nothing to go back to
+		if !m.loading {
+			m.loadErr = nil
+		}
+	}
+	return m, nil
+}
+
+// setListGrep changes the --grep used to list tests and lists them again.
+func (m model) setListGrep(grep string) (tea.Model, tea.Cmd) {
+	currentListOptions.grep = strings.TrimSpace(grep)
+	cmd := m.startReload()
+	if jsonDataPath != "" {
+		return m, tea.Batch(cmd, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("--grep has no effect with --json-data-path")))
+	}
+	return m, cmd
+}
+
+func (m model) loadingView() string {
+	source := "Listing tests: " + commandLine(listArgs(currentListOptions))
+	switch {
+	case jsonDataPath != "":
+		source = "Reading " + jsonDataPath
+	case len(workspaceConfigs) > 0:
+		source = fmt.Sprintf("Listing tests from %d configs", len(workspaceConfigs))
+	}
+	faint := lipgloss.NewStyle().Faint(true)
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.spinner.View()+" "+ansi.Truncate(source, max(m.width-2, 10), "…"),
+		"",
+		faint.Render("ctrl+g: edit --grep • q: quit"),
+	)
+}
+
+// loadErrorView shows why listing failed, one entry per Playwright error.
+func (m model) loadErrorView() string {
+	width := max(m.width-loadErrorStyle.GetHorizontalFrameSize(), 20)
+
+	var details []string
+	var pwErrors playwrightErrors
+	if errors.As(m.loadErr, &pwErrors) {
+		for _, e := range pwErrors {
+			details = append(details, "• "+strings.TrimSpace(e.Message))
+		}
+	} else {
+		details = append(details, m.loadErr.Error())
+	}
+
+	// Leave room for the header, hints and border
+	maxLines := max(m.height-6, 3)
+	var lines []string
+	for _, line := range strings.Split(strings.Join(details, "\n\n"), "\n") {
+		lines = append(lines, ansi.Truncate(line, width, "…"))
+	}
+	if len(lines) > maxLines {
+		lines = append(lines[:maxLines-1], "…")
+	}
+
+	header := "Could not list tests"
+	if m.reloading {
+		header = m.spinner.View() + " " + header
+	}
+	hints := "ctrl+r: retry • ctrl+g: edit --grep • q: quit"
+	if !m.loading {
+		hints = "ctrl+r: retry • ctrl+g: edit --grep • esc: dismiss • q: quit"
+	}
+	body := append([]string{loadErrorHeader.Render(header), ""}, lines...)
+	body = append(body, "", lipgloss.NewStyle().Faint(true).Render(hints))
+	return loadErrorStyle.Width(width + loadErrorStyle.GetHorizontalPadding()).Render(strings.Join(body, "\n"))
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNewLoadingModel_AppliesFirstListing(t *testing.T) {
+	m := NewLoadingModel(nil, nil)
+	if !m.loading || m.Init() == nil {
+		t.Fatalf("expected the model to start listing tests in the background")
+	}
+
+	updated, _ := m.Update(dataReloadedMsg{data: presetTestData()})
+	m = updated.(model)
+	if m.loading || m.reloading || m.loadErr != nil {
+		t.Errorf("expected loading to finish, got loading=%v reloading=%v err=%v", m.loading, m.reloading, m.loadErr)
+	}
+	if len(m.originalTests) != 2 {
+		t.Errorf("expected the listed tests to be applied, got %d", len(m.originalTests))
+	}
+}
+
+func TestHandleDataReloaded_ShowsPlaywrightErrors(t *testing.T) {
+	m := NewLoadingModel(nil, nil)
+	m.width, m.height = 80, 30
+	errs := playwrightErrors{{Message: "SyntaxError: cart.spec.ts: Unexpected token"}, {Message: "Error: duplicate test title"}}
+
+	updated, _ := m.Update(dataReloadedMsg{err: fmt.Errorf("error initializing data: %w", errs)})
+	m = updated.(model)
+	if !m.loading || m.loadErr == nil {
+		t.Fatalf("expected the error panel while still waiting for data")
+	}
+	view := m.View()
+	for _, want := range []string{"Could not list tests", "• SyntaxError: cart.spec.ts", "• Error: duplicate test title"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected error panel to contain %q:\n%s", want, view)
+		}
+	}
+
+	// Without any data yet, esc has nothing to return to
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(model).loadErr == nil {
+		t.Errorf("expected esc to keep the panel before the first listing")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if m = updated.(model); !m.reloading || cmd == nil {
+		t.Errorf("expected ctrl+r to list the tests again")
+	}
+}
+
+func TestSetListGrep_ReloadsWithNewGrep(t *testing.T) {
+	isolateUserDirs(t)
+	oldOpts, oldConfig := currentListOptions, configPath
+	defer func() { currentListOptions, configPath = oldOpts, oldConfig }()
+	currentListOptions = listOptions{projects: []string{"chromium"}}
+	configPath = ""
+
+	m := NewModel(presetTestData(), nil, nil)
+	updated, cmd := m.setListGrep(" @smoke ")
+	if m = updated.(model); !m.reloading || cmd == nil {
+		t.Errorf("expected a reload to start")
+	}
+
+	want := []string{"test", "--list", "--reporter=json", "--grep", "@smoke", "--project", "chromium"}
+	if got := listArgs(currentListOptions); !reflect.DeepEqual(got, want) {
+		t.Errorf("listArgs = %v; want %v", got, want)
+	}
+}
@@ -2,19 +2,50 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	pwData, projects, extraArgs, err := prepareData()
-	if err != nil {
-		fmt.Println("Error:", err)
+	if len(os.Args) > 1 && os.Args[1] == "list" {
+		if err := runList(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
 		return
 	}
 
-	p := tea.NewProgram(NewModel(pwData, projects, extraArgs))
-	if err := p.Start(); err != nil {
-		fmt.Println("Error running program:", err)
+	projects, extraArgs, err := parseArgs(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+
+	var opts []tea.ProgramOption
+	if printOnly {
+		// Keep stdout clean for the printed command when used in a pipeline
+		opts = append(opts, tea.WithOutput(os.Stderr))
+	}
+
+	// Tests are listed in the background once the TUI is up
+	p := tea.NewProgram(NewLoadingModel(projects, extraArgs), opts...)
+	final, err := p.Run()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error running program:", err)
+		os.Exit(1)
+	}
+	m, ok := final.(model)
+	if !ok {
+		return
+	}
+	if m.watcher != nil {
+		m.watcher.stop()
+	}
+	if m.printCommand != "" {
+		fmt.Println(m.printCommand)
+	} else if printOnly {
+		// Quitting without choosing must not look like an empty command
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type optionKind int
+
+const (
+	optionToggle optionKind = iota
+	// optionCount takes a whole number
+	optionCount
+	optionText
+)
+
+// runOption is a Playwright flag that can be set from the options panel.
+// Toggles with a value, like --trace on, always pass that value.
+type runOption struct {
+	flag    string
+	kind    optionKind
+	help    string
+	enabled bool
+	value   string
+	// execOnly options only work in runs handed over to Playwright: --debug
+	// and --ui need the terminal, and --reporter would replace the reporter
+	// runs inside pwgo read
+	execOnly bool
+}
+
+var (
+	optionsTitleStyle  = lipgloss.NewStyle().Background(lipgloss.Color("62")).Foreground(lipgloss.Color("230")).Padding(0, 1)
+	optionsCursorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Bold(true)
+	optionsFaintStyle  = lipgloss.NewStyle().Faint(true)
+)
+
+func defaultRunOptions() []runOption {
+	return []runOption{
+		{flag: "--headed", help: "Run tests in headed browsers"},
+		{flag: "--debug", help: "Run with the Playwright Inspector", execOnly: true},
+		{flag: "--ui", help: "Open UI mode", execOnly: true},
+		{flag: "--trace", value: "on", help: "Record a trace for every test"},
+		{flag: "--update-snapshots", help: "Update snapshots with the actual results"},
+		{flag: "--repeat-each", kind: optionCount, help: "Run each test N times"},
+		{flag: "--retries", kind: optionCount, help: "Retry failed tests up to N times"},
+		{flag: "--workers", kind: optionText, help: "Number of workers, or a percentage of CPUs"},
+		{flag: "--timeout", kind: optionCount, help: "Test timeout in milliseconds"},
+		{flag: "--max-failures", kind: optionCount, help: "Stop after N failures"},
+		{flag: "--reporter", kind: optionText, help: "Reporter to use, e.g. list, line or html", execOnly: true},
+	}
+}
+
+func (o runOption) set() bool {
+	if o.kind == optionToggle {
+		return o.enabled
+	}
+	return o.value != ""
+}
+
+func (o runOption) args() []string {
+	if !o.set() {
+		return nil
+	}
+	if o.value != "" {
+		return []string{o.flag, o.value}
+	}
+	return []string{o.flag}
+}
+
+// optionArgs returns the Playwright arguments for the options that are set.
+func (m model) optionArgs() []string {
+	var args []string
+	for _, o := range m.options {
+		args = append(args, o.args()...)
+	}
+	return args
+}
+
+// execOnlyFlag returns the first flag of the run that runs inside pwgo
+// cannot use, as they read Playwright's output through their own reporter.
+// History replays are checked against the arguments they were recorded with.
+func (m model) execOnlyFlag() (string, bool) {
+	if past, ok := m.highlightedHistory(); ok {
+		args := append([]string{}, past.Args...)
+		for _, r := range past.Runs {
+			args = append(args, r.Args...)
+		}
+		return execOnlyArg(args)
+	}
+	for _, o := range m.options {
+		if o.execOnly && o.set() {
+			return o.flag, true
+		}
+	}
+	_, extraArgs := m.runItems()
+	return execOnlyArg(append(append([]string{}, extraArgs...), m.selectedExtraArgs()...))
+}
+
+// execOnlyArg returns the first flag in args that runs inside pwgo cannot use.
+func execOnlyArg(args []string) (string, bool) {
+	for _, o := range defaultRunOptions() {
+		if o.execOnly && hasFlag(args, o.flag) {
+			return o.flag, true
+		}
+	}
+	return "", false
+}
+
+// inAppRunRefused is the status shown when an exec-only flag stops a run
+// inside pwgo.
+func inAppRunRefused(flag string) string {
+	if flag == "--reporter" {
+		return statusRemoveStyle("--reporter would replace the one pwgo reads; press enter to run with it")
+	}
+	return statusRemoveStyle(flag + " needs the terminal; press enter to run it there")
+}
+
+// setOption validates and stores the value typed for a value option. An
+// empty value clears it.
+func (m *model) setOption(idx int, value string) error {
+	value = strings.TrimSpace(value)
+	o := &m.options[idx]
+	if o.kind == optionCount && value != "" {
+		if n, err := strconv.Atoi(value); err != nil || n < 0 {
+			return fmt.Errorf("%s takes a whole number", o.flag)
+		}
+	}
+	o.value = value
+	return nil
+}
+
+// updateOptions handles keys on the options panel.
+func (m model) updateOptions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.optionsErr = ""
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "o", "q":
+		m.showOptions = false
+	case "up", "k":
+		m.optionCursor = (m.optionCursor + len(m.options) - 1) % len(m.options)
+	case "down", "j":
+		m.optionCursor = (m.optionCursor + 1) % len(m.options)
+	case " ", "enter":
+		o := &m.options[m.optionCursor]
+		if o.kind == optionToggle {
+			o.enabled = !o.enabled
+			break
+		}
+		return m, m.openPrompt(promptOption, o.flag+":", o.value)
+	case "backspace", "delete":
+		o := &m.options[m.optionCursor]
+		if o.kind == optionToggle {
+			o.enabled = false
+		} else {
+			o.value = ""
+		}
+	}
+	return m, nil
+}
+
+func (m model) submitOption(value string) (tea.Model, tea.Cmd) {
+	if err := m.setOption(m.optionCursor, value); err != nil {
+		m.optionsErr = err.Error()
+	}
+	return m, nil
+}
+
+// optionsView lists the options with the command they produce underneath.
+func (m model) optionsView(height int) string {
+	width := max(m.width, 20)
+	flagWidth := 0
+	for _, o := range m.options {
+		flagWidth = max(flagWidth, len(o.flag))
+	}
+
+	values := make([]string, len(m.options))
+	valueWidth := 0
+	for i, o := range m.options {
+		switch {
+		case o.kind == optionToggle && o.enabled:
+			values[i] = statusSelectStyle("[x]") + " " + o.value
+		case o.kind == optionToggle:
+			values[i] = "[ ] " + o.value
+		case o.value != "":
+			values[i] = statusSelectStyle(o.value)
+		default:
+			values[i] = optionsFaintStyle.Render("–")
+		}
+		valueWidth = max(valueWidth, lipgloss.Width(values[i]))
+	}
+
+	lines := []string{optionsTitleStyle.Render("Run options"), ""}
+	for i, o := range m.options {
+		cursor := "  "
+		if i == m.optionCursor {
+			cursor = optionsCursorStyle.Render("▸ ")
+		}
+		padding := strings.Repeat(" ", valueWidth-lipgloss.Width(values[i]))
+		lines = append(lines, fmt.Sprintf("%s%-*s  %s%s  %s", cursor, flagWidth, o.flag, values[i], padding, optionsFaintStyle.Render(o.help)))
+	}
+
+	lines = append(lines, "")
+	if m.optionsErr != "" {
+		lines = append(lines, statusRemoveStyle(m.optionsErr))
+	}
+	if command, ok := m.shownCommandLine(); ok {
+		lines = append(lines, optionsFaintStyle.Render("Command:"), lipgloss.NewStyle().Width(width).Render(command))
+	} else {
+		lines = append(lines, optionsFaintStyle.Render("Select items to see the command"))
+	}
+	lines = append(lines, "", optionsFaintStyle.Render("space/enter: toggle or edit • backspace: clear • esc: back"))
+	return lipgloss.NewStyle().MaxHeight(max(height, 1)).Render(strings.Join(lines, "\n"))
+}
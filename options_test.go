@@ -0,0 +1,99 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRunOptions_AppliedToRunArgs(t *testing.T) {
+	isolateUserDirs(t)
+	m := NewModel(presetTestData(), nil, []string{"--workers=1"})
+	m.lists[m.selectedIdx()].InsertItem(0, m.originalTests[0])
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	m = updated.(model)
+	if !m.showOptions {
+		t.Fatalf("expected o to open the options panel")
+	}
+	// Toggle --headed, then --trace on further down
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeySpace},
+		{Type: tea.KeyDown}, {Type: tea.KeyDown}, {Type: tea.KeyDown},
+		{Type: tea.KeyEnter},
+	} {
+		updated, _ = m.Update(msg)
+		m = updated.(model)
+	}
+	m.optionCursor = 7 // --workers
+	if err := m.setOption(m.optionCursor, " 4 "); err != nil {
+		t.Fatalf("setOption failed: %v", err)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(model)
+	if m.showOptions {
+		t.Errorf("expected esc to close the options panel")
+	}
+
+	// Options come after the CLI arguments so they take precedence
+	args, _ := m.runArgs()
+	want := []string{"test", "--workers=1", "--headed", "--trace", "on", "--workers", "4", "cart.spec.ts:3"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("runArgs = %v; want %v", args, want)
+	}
+}
+
+func TestSetOption_Validates(t *testing.T) {
+	isolateUserDirs(t)
+	m := NewModel(presetTestData(), nil, nil)
+	for i, o := range m.options {
+		if o.flag == "--retries" {
+			if err := m.setOption(i, "two"); err == nil {
+				t.Errorf("expected a count option to reject %q", "two")
+			}
+			if err := m.setOption(i, "2"); err != nil || !reflect.DeepEqual(m.options[i].args(), []string{"--retries", "2"}) {
+				t.Errorf("expected --retries 2, got %v (%v)", m.options[i].args(), err)
+			}
+			if err := m.setOption(i, ""); err != nil || m.options[i].args() != nil {
+				t.Errorf("expected an empty value to clear the option")
+			}
+		}
+	}
+}
+
+func TestExecOnlyOptions_RefusedInApp(t *testing.T) {
+	isolateUserDirs(t)
+	for _, key := range []rune{'r', 'P'} {
+		m := NewModel(presetTestData(), nil, nil)
+		m.lists[m.selectedIdx()].InsertItem(0, m.originalTests[0])
+		m.options[2].enabled = true // --ui
+
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+		m = updated.(model)
+		if m.prompt != promptNone || m.runEntry.Args != nil {
+			t.Errorf("%c: expected the run to be refused with --ui set", key)
+		}
+		if flag, ok := m.execOnlyFlag(); !ok || flag != "--ui" {
+			t.Errorf("%c: execOnlyFlag = %q, %v; want --ui", key, flag, ok)
+		}
+	}
+
+	m := NewModel(presetTestData(), nil, []string{"--debug"})
+	if flag, ok := m.execOnlyFlag(); !ok || flag != "--debug" {
+		t.Errorf("execOnlyFlag = %q, %v; want --debug from the CLI arguments", flag, ok)
+	}
+
+	// A reporter from the config file would replace the one in-app runs read
+	m = NewModel(presetTestData(), nil, []string{"--reporter=html"})
+	m.lists[m.selectedIdx()].InsertItem(0, m.originalTests[0])
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updated.(model)
+	if m.runEntry.Args != nil {
+		t.Errorf("expected the run to be refused with --reporter set")
+	}
+	if flag, ok := m.execOnlyFlag(); !ok || flag != "--reporter" {
+		t.Errorf("execOnlyFlag = %q, %v; want --reporter", flag, ok)
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const presetsFileName = ".pwgo-presets.json"
+
+// presetName is the preset to load into the Selected list at startup.
+var presetName string
+
+// savedSelection is the serialised form of a Selected list. Tests and
+// suites are stored by file:line, everything else by title.
+type savedSelection struct {
+	Tests    []string `json:"tests,omitempty"`
+	Suites   []string `json:"suites,omitempty"`
+	Files    []string `json:"files,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Projects []string `json:"projects,omitempty"`
+	Queries  []string `json:"queries,omitempty"`
+	Args     []string `json:"args,omitempty"`
+	// Exclude holds the entries marked as excluded
+	Exclude *savedSelection `json:"exclude,omitempty"`
+}
+
+// presetsPath keeps presets next to the config file so they can be shared,
+// falling back to the cwd.
+func presetsPath() string {
+	if userConfig.path != "" {
+		return filepath.Join(filepath.Dir(userConfig.path), presetsFileName)
+	}
+	return presetsFileName
+}
+
+func loadPresets(path string) (map[string]savedSelection, error) {
+	presets := map[string]savedSelection{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return presets, nil
+	}
+	if err != nil {
+		return presets, err
+	}
+	if err := json.Unmarshal(data, &presets); err != nil {
+		return presets, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	return presets, nil
+}
+
+func savePresets(path string, presets map[string]savedSelection) error {
+	data, err := json.MarshalIndent(presets, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func newSavedSelection(items []item, args []string) savedSelection {
+	var s savedSelection
+	var excluded []item
+	for _, it := range items {
+		if it.stale {
+			continue
+		}
+		if it.excluded {
+			it.excluded = false
+			excluded = append(excluded, it)
+			continue
+		}
+		switch it.source {
+		case "Tests":
+			s.Tests = append(s.Tests, it.description)
+		case "Suites":
+			s.Suites = append(s.Suites, it.description)
+		case "Files":
+			s.Files = append(s.Files, it.title)
+		case "Tags":
+			s.Tags = append(s.Tags, it.title)
+		case "Projects":
+			s.Projects = append(s.Projects, it.title)
+		case "Queries":
+			s.Queries = append(s.Queries, it.title)
+		}
+	}
+	s.Args = args
+	if len(excluded) > 0 {
+		exclude := newSavedSelection(excluded, nil)
+		s.Exclude = &exclude
+	}
+	return s
+}
+
+func (s savedSelection) summary() string {
+	var parts []string
+	for _, c := range []struct {
+		n      int
+		source string
+	}{
+		{len(s.Tests), "Tests"}, {len(s.Suites), "Suites"}, {len(s.Files), "Files"},
+		{len(s.Tags), "Tags"}, {len(s.Projects), "Projects"}, {len(s.Queries), "Queries"},
+	} {
+		switch {
+		case c.n == 1:
+			parts = append(parts, "1 "+sourceNoun(c.source))
+		case c.n > 1:
+			parts = append(parts, fmt.Sprintf("%d %s", c.n, strings.ToLower(c.source)))
+		}
+	}
+	if s.Exclude != nil {
+		parts = append(parts, "excluding "+s.Exclude.summary())
+	}
+	if len(s.Args) > 0 {
+		parts = append(parts, strings.Join(s.Args, " "))
+	}
+	if len(parts) == 0 {
+		return "empty"
+	}
+	return strings.Join(parts, " · ")
+}
+
+// resolve matches a saved selection against the current list data. Entries
+// that no longer exist in the --list output come back marked stale.
+func (m model) resolve(s savedSelection) (items []item, stale int) {
+	lookup := func(entries []string, source string, original []item, match func(item) string) {
+		for _, entry := range entries {
+			found := false
+			for _, orig := range original {
+				if match(orig) == entry {
+					items = append(items, orig)
+					found = true
+					break
+				}
+			}
+			if !found {
+				items = append(items, item{title: entry, description: "not found in --list output", source: source, stale: true})
+				stale++
+			}
+		}
+	}
+	byTitle := func(it item) string { return it.title }
+	byLocation := func(it item) string { return it.description }
+
+	lookup(s.Tests, "Tests", m.originalTests, byLocation)
+	lookup(s.Suites, "Suites", m.suiteItems(), byLocation)
+	lookup(s.Files, "Files", m.originalFiles, byTitle)
+	lookup(s.Tags, "Tags", m.originalTags, byTitle)
+	lookup(s.Projects, "Projects", m.originalProjects, byTitle)
+	for _, query := range s.Queries {
+		expr, err := parseTagQuery(query)
+		if err != nil {
+			items = append(items, item{title: query, description: "invalid tag query", source: "Queries", stale: true})
+			stale++
+			continue
+		}
+		items = append(items, newQueryItem(expr, matchTagQuery(expr, m.originalTests)))
+	}
+	if s.Exclude != nil {
+		excluded, excludedStale := m.resolve(*s.Exclude)
+		for _, it := range excluded {
+			it.excluded = true
+			items = append(items, it)
+		}
+		stale += excludedStale
+	}
+	return items, stale
+}
+
+// suiteItems returns the selection item of every describe block in the tree.
+func (m model) suiteItems() []item {
+	var items []item
+	var walk func(nodes []*treeNode)
+	walk = func(nodes []*treeNode) {
+		for _, n := range nodes {
+			if n.spec == nil && !n.isFile() {
+				items = append(items, n.selectionItem())
+			}
+			walk(n.children)
+		}
+	}
+	walk(m.tree)
+	return items
+}
+
+func newPresetList(presets map[string]savedSelection) list.Model {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := make([]list.Item, len(names))
+	for i, name := range names {
+		items[i] = item{title: name, description: presets[name].summary(), source: "Presets"}
+	}
+
+	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Presets"
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keyMap.Submit, keyMap.Load}
+	}
+	l.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keyMap.Submit, keyMap.Load, keyMap.ToggleLeft, keyMap.ToggleRight, keyMap.SavePreset}
+	}
+	return l
+}
+
+// loadPreset adds a preset's entries to the Selected list and merges its
+// extra arguments into the run.
+func (m *model) loadPreset(name string) string {
+	s, ok := m.presets[name]
+	if !ok {
+		return statusRemoveStyle(fmt.Sprintf("Preset %q not found", name))
+	}
+
+	stale := m.loadSelection(s)
+	if stale == 1 {
+		return statusRemoveStyle(fmt.Sprintf("Loaded preset %q with 1 stale entry", name))
+	}
+	if stale > 1 {
+		return statusRemoveStyle(fmt.Sprintf("Loaded preset %q with %d stale entries", name, stale))
+	}
+	return statusSelectStyle(fmt.Sprintf("Loaded preset %q", name))
+}
+
+// loadSelection adds saved entries to the Selected list and merges their
+// extra arguments into those that go with it, returning how many entries are
+// stale.
+func (m *model) loadSelection(s savedSelection) int {
+	if len(m.lists[m.selectedIdx()].Items()) == 0 {
+		// The arguments of a selection that has been cleared go with it
+		m.selectionArgs = nil
+	}
+	items, stale := m.resolve(s)
+	selected := m.selectedKeys()
+	for _, it := range items {
+		if _, ok := selected[itemKey(it)]; ok {
+			continue
+		}
+		m.lists[m.selectedIdx()].InsertItem(len(m.lists[m.selectedIdx()].Items()), it)
+		selected[itemKey(it)] = struct{}{}
+	}
+	m.selectionArgs = mergeArgs(m.selectionArgs, s.Args)
+	m.refreshLists()
+	return stale
+}
+
+func (m model) savePreset(name string) (tea.Model, tea.Cmd) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("Preset name cannot be empty"))
+	}
+
+	var items []item
+	for _, li := range m.lists[m.selectedIdx()].Items() {
+		items = append(items, li.(item))
+	}
+	m.presets[name] = newSavedSelection(items, m.selectedExtraArgs())
+	if err := savePresets(presetsPath(), m.presets); err != nil {
+		return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("Error saving preset: " + err.Error()))
+	}
+
+	idx := m.listIdx("Presets")
+	m.lists[idx].SetItems(newPresetList(m.presets).Items())
+	return m, m.lists[m.focusedIdx].NewStatusMessage(statusSelectStyle(fmt.Sprintf("Saved preset %q", name)))
+}
+
+// selectedExtraArgs returns the extra arguments for running the Selected list,
+// which include those of any preset or past run loaded into it while it still
+// has items.
+func (m model) selectedExtraArgs() []string {
+	if len(m.lists[m.selectedIdx()].Items()) == 0 {
+		return m.extraArgs
+	}
+	return mergeArgs(m.extraArgs, m.selectionArgs)
+}
+
+// mergeArgs returns base followed by extra, with each flag of extra and its
+// value replacing the same flag in base.
+func mergeArgs(base, extra []string) []string {
+	return append(overriddenArgsRemoved(base, extra), extra...)
+}
+
+func containsString(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func presetTestData() PlaywrightJSON {
+	return PlaywrightJSON{
+		Suites: []Suite{{
+			Title: "cart.spec.ts",
+			File:  "cart.spec.ts",
+			Suites: []Suite{{
+				Title: "Cart",
+				File:  "cart.spec.ts",
+				Line:  2,
+				Specs: []Spec{
+					{Title: "adds", File: "cart.spec.ts", Line: 3, Tags: []string{"@smoke"}, Tests: []TestInstance{{ProjectName: "chromium"}}},
+					{Title: "removes", File: "cart.spec.ts", Line: 8, Tests: []TestInstance{{ProjectName: "chromium"}}},
+				},
+			}},
+		}},
+	}
+}
+
+func TestPresets_SaveAndLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), presetsFileName)
+	presets := map[string]savedSelection{
+		"smoke": newSavedSelection([]item{
+			{source: "Tests", title: "Cart › adds", description: "cart.spec.ts:3"},
+			{source: "Suites", title: "Cart", description: "cart.spec.ts:2"},
+			{source: "Tags", title: "@smoke"},
+			{source: "Projects", title: "chromium"},
+			{source: "Tests", title: "gone", description: "not found in --list output", stale: true},
+		}, []string{"--headed"}),
+	}
+	if err := savePresets(path, presets); err != nil {
+		t.Fatalf("savePresets failed: %v", err)
+	}
+
+	loaded, err := loadPresets(path)
+	if err != nil {
+		t.Fatalf("loadPresets failed: %v", err)
+	}
+	got := loaded["smoke"]
+	if len(got.Tests) != 1 || got.Tests[0] != "cart.spec.ts:3" || len(got.Suites) != 1 || len(got.Tags) != 1 || len(got.Projects) != 1 {
+		t.Errorf("unexpected round-tripped preset: %+v", got)
+	}
+	if got.summary() != "1 test · 1 suite · 1 tag · 1 project · --headed" {
+		t.Errorf("unexpected summary: %q", got.summary())
+	}
+
+	if missing, err := loadPresets(filepath.Join(t.TempDir(), "none.json")); err != nil || len(missing) != 0 {
+		t.Errorf("expected an empty preset map for a missing file, got %v, %v", missing, err)
+	}
+}
+
+func TestModel_LoadPresetFlagsStaleEntries(t *testing.T) {
+	isolateUserDirs(t)
+	m := NewModel(presetTestData(), nil, nil)
+	m.presets = map[string]savedSelection{
+		"cart": {
+			Tests:  []string{"cart.spec.ts:3", "cart.spec.ts:99"},
+			Suites: []string{"cart.spec.ts:2"},
+			Tags:   []string{"@gone"},
+			Args:   []string{"--headed"},
+		},
+	}
+
+	status := m.loadPreset("cart")
+	if status != statusRemoveStyle(`Loaded preset "cart" with 2 stale entries`) {
+		t.Errorf("unexpected status: %q", status)
+	}
+
+	selected := m.lists[m.selectedIdx()].Items()
+	if len(selected) != 4 {
+		t.Fatalf("expected 4 selected items, got %d", len(selected))
+	}
+	stale := 0
+	for _, li := range selected {
+		if li.(item).stale {
+			stale++
+		}
+	}
+	if stale != 2 {
+		t.Errorf("expected 2 stale items, got %d", stale)
+	}
+
+	args, ok := m.runArgs()
+	if !ok {
+		t.Fatalf("expected run args")
+	}
+	for _, arg := range args {
+		if arg == "cart.spec.ts:99" || arg == "@gone" {
+			t.Errorf("stale entry %q should not be passed to Playwright: %v", arg, args)
+		}
+	}
+	if !containsString(args, "--headed") || !containsString(args, "cart.spec.ts:8") {
+		t.Errorf("expected preset args and suite specs, got %v", args)
+	}
+
+	// Clearing the Selected list drops the preset's args
+	m.lists[m.selectedIdx()].SetItems(nil)
+	m.lists[m.listIdx("Tests")].Select(0)
+	if args, _ := m.runArgs(); containsString(args, "--headed") {
+		t.Errorf("expected the preset args to go with its selection, got %v", args)
+	}
+	m.loadSelection(savedSelection{Tests: []string{"cart.spec.ts:3"}})
+	if args, _ := m.runArgs(); containsString(args, "--headed") {
+		t.Errorf("expected a new selection to start without the preset args, got %v", args)
+	}
+}
+
+func TestModel_SavePresetPrompt(t *testing.T) {
+	isolateUserDirs(t)
+	chdir(t, t.TempDir())
+	userConfig = pwgoConfig{}
+
+	m := NewModel(presetTestData(), nil, nil)
+	m.lists[m.selectedIdx()].InsertItem(0, m.originalTests[0])
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(model)
+	if m.prompt != promptPresetName {
+		t.Fatalf("expected the preset name prompt to open")
+	}
+	for _, r := range "quick" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(model)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+
+	presets, err := loadPresets(presetsFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := presets["quick"]; len(got.Tests) != 1 || got.Tests[0] != "cart.spec.ts:3" {
+		t.Errorf("expected saved preset with one test, got %+v", presets)
+	}
+	if len(m.lists[m.listIdx("Presets")].Items()) != 1 {
+		t.Errorf("expected the Presets list to show the new preset")
+	}
+}
+
+func TestPrepareData_UnknownPreset(t *testing.T) {
+	chdir(t, t.TempDir())
+	jsonPath := writeTempJSON(t, presetTestData())
+	defer os.Remove(jsonPath)
+
+	oldArgs := os.Args
+	defer func() {
+		os.Args = oldArgs
+		presetName = ""
+	}()
+	os.Args = []string{"cmd", "--json-data-path", jsonPath, "--preset", "missing"}
+
+	if _, _, _, err := prepareData(); err == nil {
+		t.Errorf("expected an error for an unknown preset")
+	}
+}
+
+func TestMergeArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		base, extra []string
+		want        []string
+	}{
+		{"shared value", []string{"--workers", "4"}, []string{"--retries", "4"}, []string{"--workers", "4", "--retries", "4"}},
+		{"same flag", []string{"--workers", "2"}, []string{"--workers", "4"}, []string{"--workers", "4"}},
+		{"same flag with =", []string{"--workers=2", "--headed"}, []string{"-j", "4"}, []string{"--headed", "-j", "4"}},
+		{"same toggle", []string{"--headed"}, []string{"--headed"}, []string{"--headed"}},
+		{"nothing to add", []string{"--headed"}, nil, []string{"--headed"}},
+	}
+	for _, tt := range tests {
+		if got := mergeArgs(tt.base, tt.extra); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: mergeArgs(%v, %v) = %v; want %v", tt.name, tt.base, tt.extra, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// previewMinWidth is the narrowest terminal that still gets a split layout.
+const previewMinWidth = 80
+
+var (
+	previewStyle = lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(lipgloss.Color("240")).
+			PaddingLeft(1)
+	previewGutterStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	previewLineStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Bold(true)
+	previewHeaderStyle = lipgloss.NewStyle().Faint(true)
+)
+
+// previewFile is a spec file read from disk and highlighted line by line.
+type previewFile struct {
+	lines []string
+	err   error
+	// modTime and size tell when the file has changed since it was read
+	modTime time.Time
+	size    int64
+}
+
+// previewTarget returns the file and line to preview for an item. Line is 0
+// for whole files; tags, projects and presets have no source to show.
+func previewTarget(it item) (file string, line int, ok bool) {
+	if it.node != nil {
+		return it.node.file, it.node.line, it.node.file != ""
+	}
+	switch it.source {
+	case "Files":
+		return it.title, 0, !it.stale
+	case "Tests", "Suites":
+		if it.stale {
+			return "", 0, false
+		}
+		file = specFile(it)
+		line = it.line
+		if line == 0 {
+			line, _ = strconv.Atoi(strings.TrimPrefix(it.description, file+":"))
+		}
+		return file, line, file != ""
+	}
+	return "", 0, false
+}
+
+// resolveSpecPath maps a spec path from the --list output, which is relative
+// to the Playwright rootDir, to a path on disk.
+func resolveSpecPath(rootDir, file string) string {
+	if filepath.IsAbs(file) || rootDir == "" {
+		return file
+	}
+	return filepath.Join(rootDir, file)
+}
+
+func loadPreviewFile(path string) previewFile {
+	info, err := os.Stat(path)
+	if err != nil {
+		return previewFile{err: err}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return previewFile{err: err}
+	}
+	return previewFile{
+		lines:   highlightSource(path, strings.ReplaceAll(string(data), "\t", "  ")),
+		modTime: info.ModTime(),
+		size:    info.Size(),
+	}
+}
+
+// highlightSource styles each token with lipgloss so every line carries its
+// own colours and can be sliced and truncated independently.
+func highlightSource(path, source string) []string {
+	lexer := lexers.Match(filepath.Base(path))
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, source)
+	if err != nil {
+		return strings.Split(source, "\n")
+	}
+	style := styles.Get("monokai")
+
+	lines := []string{""}
+	for _, token := range iterator.Tokens() {
+		entry := style.Get(token.Type)
+		s := lipgloss.NewStyle().Bold(entry.Bold == chroma.Yes).Italic(entry.Italic == chroma.Yes)
+		if entry.Colour.IsSet() {
+			s = s.Foreground(lipgloss.Color(entry.Colour.String()))
+		}
+		for i, part := range strings.Split(token.Value, "\n") {
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			if part != "" {
+				lines[len(lines)-1] += s.Render(part)
+			}
+		}
+	}
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// previewVisible reports whether the preview pane is shown next to the lists.
+func (m model) previewVisible() bool {
+	return m.preview && m.width >= previewMinWidth
+}
+
+// listWidth is the space left for the lists beside the preview pane.
+func (m model) listWidth() int {
+	if m.previewVisible() {
+		return m.width / 2
+	}
+	return m.width
+}
+
+// resize fits the lists to the window, leaving room for the preview pane.
+func (m *model) resize() {
+	for i := range m.lists {
+		m.lists[i].SetSize(m.listWidth(), m.height)
+	}
+	// The results list only exists once a run has finished
+	if m.showResults {
+		m.results.SetSize(m.width, m.height)
+	}
+}
+
+// previewKey identifies the highlighted item so scrolling resets when it changes.
+func (m model) previewKey() string {
+	selected := m.lists[m.focusedIdx].SelectedItem()
+	if selected == nil {
+		return ""
+	}
+	return itemKey(selected.(item))
+}
+
+// scrollPreview moves the preview by delta lines for the highlighted item,
+// stopping at either end of the file.
+func (m *model) scrollPreview(delta int) {
+	if key := m.previewKey(); key != m.previewFor {
+		m.previewFor = key
+		m.previewOffset = 0
+	}
+	selected := m.lists[m.focusedIdx].SelectedItem()
+	if selected == nil {
+		return
+	}
+	file, line, ok := previewTarget(selected.(item))
+	if !ok {
+		return
+	}
+	src := m.previewSource(file)
+	bodyHeight := previewBodyHeight(m.height)
+	start := previewStart(line, len(src.lines), bodyHeight, m.previewOffset+delta)
+	m.previewOffset = start - previewAnchor(line, bodyHeight)
+}
+
+// previewSource reads and highlights a spec file, reading it again when it
+// has changed on disk since it was cached.
+func (m *model) previewSource(file string) previewFile {
+	path := resolveSpecPath(m.rootDir, file)
+	if src, cached := m.previewCache[path]; cached {
+		info, err := os.Stat(path)
+		if err != nil && src.err != nil {
+			return src
+		}
+		if err == nil && src.err == nil && info.ModTime().Equal(src.modTime) && info.Size() == src.size {
+			return src
+		}
+	}
+	src := loadPreviewFile(path)
+	m.previewCache[path] = src
+	return src
+}
+
+// refreshPreview caches the source of the highlighted item so the view can
+// draw it without reading files.
+func (m *model) refreshPreview() {
+	if !m.previewVisible() {
+		return
+	}
+	selected := m.lists[m.focusedIdx].SelectedItem()
+	if selected == nil {
+		return
+	}
+	if file, _, ok := previewTarget(selected.(item)); ok {
+		m.previewSource(file)
+	}
+}
+
+func previewBodyHeight(height int) int {
+	return max(height-2, 1)
+}
+
+// previewAnchor is the unscrolled first line. Tests sit a third of the way
+// down so their setup is visible above them.
+func previewAnchor(line, bodyHeight int) int {
+	if line == 0 {
+		return 0
+	}
+	return line - 1 - bodyHeight/3
+}
+
+// previewStart is the first line shown after scrolling by offset.
+func previewStart(line, total, bodyHeight, offset int) int {
+	start := previewAnchor(line, bodyHeight) + offset
+	return min(max(start, 0), max(total-bodyHeight, 0))
+}
+
+func (m model) previewView(width, height int) string {
+	width = max(width-previewStyle.GetHorizontalFrameSize(), 10)
+	render := func(lines ...string) string {
+		return previewStyle.Height(height).Render(strings.Join(lines, "\n"))
+	}
+
+	selected := m.lists[m.focusedIdx].SelectedItem()
+	if selected == nil {
+		return render(previewHeaderStyle.Render("Nothing to preview"))
+	}
+	file, line, ok := previewTarget(selected.(item))
+	if !ok {
+		return render(previewHeaderStyle.Render("No source to preview"))
+	}
+
+	src, cached := m.previewCache[resolveSpecPath(m.rootDir, file)]
+	if !cached {
+		return render(previewHeaderStyle.Render(file))
+	}
+	if src.err != nil {
+		return render(previewHeaderStyle.Render(file), statusRemoveStyle(src.err.Error()))
+	}
+
+	header := file
+	if line > 0 {
+		header = fmt.Sprintf("%s:%d", file, line)
+	}
+	bodyHeight := previewBodyHeight(height)
+	offset := 0
+	if m.previewFor == itemKey(selected.(item)) {
+		offset = m.previewOffset
+	}
+	start := previewStart(line, len(src.lines), bodyHeight, offset)
+	end := min(start+bodyHeight, len(src.lines))
+
+	gutter := len(strconv.Itoa(len(src.lines)))
+	lines := []string{previewHeaderStyle.Render(ansi.Truncate(header, width, "…")), ""}
+	for i := start; i < end; i++ {
+		number := fmt.Sprintf("%*d ", gutter, i+1)
+		if i+1 == line {
+			number = previewLineStyle.Render(fmt.Sprintf("%*d▸", gutter, i+1))
+		} else {
+			number = previewGutterStyle.Render(number)
+		}
+		lines = append(lines, number+" "+ansi.Truncate(src.lines[i], width-gutter-2, ""))
+	}
+	return render(lines...)
+}
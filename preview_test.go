@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestPreviewTarget(t *testing.T) {
+	tests := []struct {
+		it   item
+		file string
+		line int
+		ok   bool
+	}{
+		{item{source: "Tests", description: "e2e/cart.spec.ts:12", line: 12}, "e2e/cart.spec.ts", 12, true},
+		{item{source: "Suites", description: "e2e/cart.spec.ts:4"}, "e2e/cart.spec.ts", 4, true},
+		{item{source: "Files", title: "e2e/cart.spec.ts"}, "e2e/cart.spec.ts", 0, true},
+		{item{source: "Tests", description: "not found in --list output", stale: true}, "", 0, false},
+		{item{source: "Tags", title: "@smoke"}, "", 0, false},
+		{item{node: &treeNode{file: "a.spec.ts", line: 7}}, "a.spec.ts", 7, true},
+	}
+	for _, tt := range tests {
+		file, line, ok := previewTarget(tt.it)
+		if file != tt.file || line != tt.line || ok != tt.ok {
+			t.Errorf("previewTarget(%+v) = %q, %d, %v; want %q, %d, %v", tt.it, file, line, ok, tt.file, tt.line, tt.ok)
+		}
+	}
+}
+
+func TestResolveSpecPath(t *testing.T) {
+	if got := resolveSpecPath("/repo/e2e", "cart.spec.ts"); got != filepath.Join("/repo/e2e", "cart.spec.ts") {
+		t.Errorf("expected path under rootDir, got %q", got)
+	}
+	if got := resolveSpecPath("", "cart.spec.ts"); got != "cart.spec.ts" {
+		t.Errorf("expected cwd-relative path without rootDir, got %q", got)
+	}
+}
+
+func TestHighlightSource_KeepsLines(t *testing.T) {
+	source := "/* multi\n   line */\ntest('adds', async () => {\n\texpect(1).toBe(1);\n});\n"
+	lines := highlightSource("cart.spec.ts", source)
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 lines, got %d: %q", len(lines), lines)
+	}
+	if got := ansi.Strip(lines[2]); got != "test('adds', async () => {" {
+		t.Errorf("unexpected line text: %q", got)
+	}
+}
+
+func TestPreviewView_ShowsTestLine(t *testing.T) {
+	isolateUserDirs(t)
+	dir := t.TempDir()
+	var source strings.Builder
+	for i := 1; i <= 40; i++ {
+		source.WriteString("// line\n")
+	}
+	os.WriteFile(filepath.Join(dir, "cart.spec.ts"), []byte(source.String()), 0o644)
+
+	data := presetTestData()
+	data.Config.RootDir = dir
+	m := NewModel(data, nil, nil)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	m = updated.(model)
+
+	view := ansi.Strip(m.previewView(60, 12))
+	if !strings.Contains(view, "cart.spec.ts:3") || !strings.Contains(view, " 3▸") {
+		t.Errorf("expected the preview to mark line 3:\n%s", view)
+	}
+
+	m.scrollPreview(100)
+	view = ansi.Strip(m.previewView(60, m.height))
+	if strings.Contains(view, " 3▸") || !strings.Contains(view, "40 ") {
+		t.Errorf("expected the preview to scroll to the end of the file:\n%s", view)
+	}
+	m.scrollPreview(-1)
+	if view = ansi.Strip(m.previewView(60, m.height)); strings.Contains(view, "40 ") {
+		t.Errorf("expected scrolling up to move straight back from the end:\n%s", view)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if updated.(model).previewVisible() {
+		t.Errorf("expected p to hide the preview")
+	}
+}
+
+func TestPreviewView_ReadsFilesEditedElsewhere(t *testing.T) {
+	isolateUserDirs(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cart.spec.ts")
+	os.WriteFile(path, []byte("// before\n"), 0o644)
+
+	data := presetTestData()
+	data.Config.RootDir = dir
+	m := NewModel(data, nil, nil)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	m = updated.(model)
+	if view := ansi.Strip(m.previewView(60, 12)); !strings.Contains(view, "before") {
+		t.Fatalf("expected the file's source in the preview:\n%s", view)
+	}
+
+	os.WriteFile(path, []byte("// after the edit\n"), 0o644)
+	updated, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	m = updated.(model)
+	if view := ansi.Strip(m.previewView(60, 12)); !strings.Contains(view, "after the edit") {
+		t.Errorf("expected the edited source in the preview:\n%s", view)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build !windows
+
+package main
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// ownProcessGroup starts cmd in a process group of its own, so stopping it
+// also stops the workers and browsers it launches.
+func ownProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// stopProcessGroup interrupts cmd and everything in its process group, as
+// Ctrl+C in a terminal would, so Playwright can close browsers and web
+// servers and write its reports. Whatever is left after stopGracePeriod is
+// killed.
+func stopProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	pid := cmd.Process.Pid
+	if err := syscall.Kill(-pid, syscall.SIGINT); err != nil {
+		cmd.Process.Kill()
+		return
+	}
+	time.AfterFunc(stopGracePeriod, func() {
+		syscall.Kill(-pid, syscall.SIGKILL)
+	})
+}
@@ -0,0 +1,36 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStopProcessGroup_LetsTheRunShutDown(t *testing.T) {
+	done := filepath.Join(t.TempDir(), "done")
+	cmd := exec.Command("sh", "-c", `trap 'echo bye > "$DONE"; exit 1' INT; while :; do sleep 0.1; done`)
+	cmd.Env = append(os.Environ(), "DONE="+done)
+	ownProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	// Give the shell time to set its trap
+	time.Sleep(200 * time.Millisecond)
+
+	stopProcessGroup(cmd)
+	select {
+	case <-exited:
+	case <-time.After(stopGracePeriod / 2):
+		cmd.Process.Kill()
+		t.Fatalf("expected the run to stop before the grace period ran out")
+	}
+	if _, err := os.Stat(done); err != nil {
+		t.Errorf("expected the run to shut down cleanly: %v", err)
+	}
+}
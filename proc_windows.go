@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+func ownProcessGroup(cmd *exec.Cmd) {}
+
+// stopProcessGroup asks cmd and the processes it started to close with
+// taskkill, as Windows has no process groups to signal, and forces whatever
+// is left after stopGracePeriod.
+func stopProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	pid := strconv.Itoa(cmd.Process.Pid)
+	force := func() {
+		if err := exec.Command("taskkill", "/T", "/F", "/PID", pid).Run(); err != nil {
+			cmd.Process.Kill()
+		}
+	}
+	// Console programs that cannot be asked to close are forced straight away
+	if err := exec.Command("taskkill", "/T", "/PID", pid).Run(); err != nil {
+		force()
+		return
+	}
+	time.AfterFunc(stopGracePeriod, force)
+}
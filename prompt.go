@@ -0,0 +1,100 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// promptKind identifies what a single-line text prompt is asking for.
+type promptKind int
+
+const (
+	promptNone promptKind = iota
+	promptPresetName
+	promptTagQuery
+	promptGrep
+	promptOption
+	promptShards
+	promptDiffRef
+)
+
+var promptStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("62")).
+	Padding(0, 1)
+
+// openPrompt shows a text prompt with the given label and initial value.
+func (m *model) openPrompt(kind promptKind, label, value string) tea.Cmd {
+	input := textinput.New()
+	input.Prompt = label + " "
+	input.SetValue(value)
+	input.CursorEnd()
+	input.Width = max(m.width-len(label)-8, 20)
+	m.prompt = kind
+	m.input = input
+	return m.input.Focus()
+}
+
+func (m *model) closePrompt() {
+	m.prompt = promptNone
+	m.input.Blur()
+}
+
+// updatePrompt handles keys while a prompt is open. Enter submits the value
+// to the prompt's handler and esc cancels.
+func (m model) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.closePrompt()
+		return m, nil
+	case "enter":
+		kind, value := m.prompt, m.input.Value()
+		m.closePrompt()
+		return m.submitPrompt(kind, value)
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m model) submitPrompt(kind promptKind, value string) (tea.Model, tea.Cmd) {
+	switch kind {
+	case promptPresetName:
+		return m.savePreset(value)
+	case promptTagQuery:
+		return m.addTagQuery(value)
+	case promptGrep:
+		return m.setListGrep(value)
+	case promptOption:
+		return m.submitOption(value)
+	case promptShards:
+		return m.startShardedRun(value)
+	case promptDiffRef:
+		return m.submitDiffRef(value)
+	}
+	return m, nil
+}
+
+func (m model) promptView() string {
+	faint := lipgloss.NewStyle().Faint(true)
+	lines := []string{m.input.View()}
+	if m.prompt == promptTagQuery {
+		lines = append(lines, faint.Render(m.tagQueryStatus(m.input.Value())))
+	}
+	if m.prompt == promptOption {
+		lines = append(lines, faint.Render(m.options[m.optionCursor].help+"; leave empty to clear"))
+	}
+	if m.prompt == promptDiffRef {
+		lines = append(lines, faint.Render("A branch, tag or commit for the Changed list to compare the working tree with"))
+	}
+	if m.prompt == promptShards {
+		lines = append(lines, faint.Render("Each shard runs as its own Playwright process with --shard=i/N"))
+	}
+	if m.prompt == promptGrep {
+		lines = append(lines, faint.Render("Lists only tests whose title matches; leave empty to list all"))
+	}
+	lines = append(lines, faint.Render("enter: confirm • esc: cancel"))
+	return promptStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// resultItem is a test outcome on the results screen.
+type resultItem struct {
+	title    string
+	file     string
+	line     int
+	project  string
+	status   runStatus
+	retries  int
+	duration time.Duration
+	errMsg   string
+}
+
+func (r resultItem) location() string {
+	return fmt.Sprintf("%s:%d", r.file, r.line)
+}
+
+func (r resultItem) failed() bool {
+	return r.status == statusFailed
+}
+
+func (r resultItem) Title() string {
+	return fmt.Sprintf("%s %s", runStatusStyle(r.status).Render(runStatusIcons[r.status]), r.title)
+}
+
+func (r resultItem) Description() string {
+	parts := []string{r.location()}
+	if r.project != "" {
+		parts = append(parts, r.project)
+	}
+	parts = append(parts, runStatusStyle(r.status).Render(string(r.status)))
+	if r.retries > 0 {
+		parts = append(parts, fmt.Sprintf("retry #%d", r.retries))
+	}
+	parts = append(parts, r.duration.Round(time.Millisecond).String())
+	desc := strings.Join(parts, " · ")
+	if r.errMsg != "" {
+		desc += "  " + statusRemoveStyle(r.errMsg)
+	}
+	return desc
+}
+
+func (r resultItem) FilterValue() string { return r.title }
+
+// readResults parses the JSON reporter output written during an in-app run.
+func readResults(path string) (PlaywrightJSON, error) {
+	var report PlaywrightJSON
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return report, err
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return report, fmt.Errorf("failed to parse JSON results: %w", err)
+	}
+	return report, nil
+}
+
+// collectResults flattens a JSON report into one entry per test and project,
+// with failures first.
+func collectResults(report PlaywrightJSON) []resultItem {
+	var results []resultItem
+	var walk func(suite Suite, parentTitle string)
+	walk = func(suite Suite, parentTitle string) {
+		fullTitle := suiteTitle(parentTitle, suite)
+		for _, spec := range suite.Specs {
+			title := fullTitle
+			if title != "" {
+				title += " › "
+			}
+			title += spec.Title
+
+			for _, test := range spec.Tests {
+				results = append(results, newResultItem(spec, test, title))
+			}
+		}
+		for _, child := range suite.Suites {
+			walk(child, fullTitle)
+		}
+	}
+	for _, suite := range report.Suites {
+		walk(suite, "")
+	}
+
+	rank := map[runStatus]int{statusFailed: 0, statusFlaky: 1, statusPassed: 2, statusSkipped: 3}
+	sort.SliceStable(results, func(i, j int) bool {
+		return rank[results[i].status] < rank[results[j].status]
+	})
+	return results
+}
+
+func newResultItem(spec Spec, test TestInstance, title string) resultItem {
+	r := resultItem{
+		title:   title,
+		file:    spec.File,
+		line:    spec.Line,
+		project: test.ProjectName,
+	}
+
+	switch test.Status {
+	case "expected":
+		r.status = statusPassed
+	case "flaky":
+		r.status = statusFlaky
+	case "skipped":
+		r.status = statusSkipped
+	default:
+		r.status = statusFailed
+	}
+
+	if len(test.Results) > 0 {
+		last := test.Results[len(test.Results)-1]
+		r.retries = last.Retry
+		r.duration = time.Duration(last.Duration) * time.Millisecond
+		for i := len(test.Results) - 1; i >= 0 && r.errMsg == ""; i-- {
+			if errs := test.Results[i].Errors; len(errs) > 0 {
+				r.errMsg = firstLine(ansi.Strip(errs[0].Message))
+			}
+		}
+	}
+	return r
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "\n"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+func newResultsList(results []resultItem) list.Model {
+	items := make([]list.Item, len(results))
+	counts := map[runStatus]int{}
+	for i, r := range results {
+		items[i] = r
+		counts[r.status]++
+	}
+
+	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	l.Title = fmt.Sprintf("Results · %d passed · %d failed · %d flaky · %d skipped",
+		counts[statusPassed], counts[statusFailed], counts[statusFlaky], counts[statusSkipped])
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keyMap.Rerun, keyMap.RerunAll, keyMap.AddFailed, keyMap.Back}
+	}
+	l.AdditionalFullHelpKeys = l.AdditionalShortHelpKeys
+	return l
+}
+
+func failedResults(items []list.Item) []resultItem {
+	var failed []resultItem
+	for _, li := range items {
+		if r := li.(resultItem); r.failed() {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// rerunGroups splits failed results by project, so a re-run only repeats each
+// test in the projects it failed in.
+func rerunGroups(failed []resultItem) [][]item {
+	var projects []string
+	byProject := map[string][]resultItem{}
+	for _, r := range failed {
+		if _, ok := byProject[r.project]; !ok {
+			projects = append(projects, r.project)
+		}
+		byProject[r.project] = append(byProject[r.project], r)
+	}
+	groups := make([][]item, len(projects))
+	for i, p := range projects {
+		groups[i] = rerunItems(byProject[p])
+	}
+	return groups
+}
+
+// rerunItems converts failed results into selection items: one test per
+// location plus the projects they failed in.
+func rerunItems(failed []resultItem) []item {
+	var items []item
+	seenTests := map[string]struct{}{}
+	seenProjects := map[string]struct{}{}
+	for _, r := range failed {
+		if _, ok := seenTests[r.location()]; !ok {
+			items = append(items, item{title: r.title, description: r.location(), line: r.line, source: "Tests"})
+			seenTests[r.location()] = struct{}{}
+		}
+		if _, ok := seenProjects[r.project]; !ok && r.project != "" {
+			items = append(items, item{title: r.project, source: "Projects"})
+			seenProjects[r.project] = struct{}{}
+		}
+	}
+	return items
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleReport() PlaywrightJSON {
+	return PlaywrightJSON{
+		Suites: []Suite{{
+			Title: "cart.spec.ts",
+			File:  "cart.spec.ts",
+			Suites: []Suite{{
+				Title: "Cart",
+				File:  "cart.spec.ts",
+				Line:  3,
+				Specs: []Spec{
+					{
+						Title: "adds item",
+						File:  "cart.spec.ts",
+						Line:  4,
+						Tests: []TestInstance{
+							{ProjectName: "chromium", Status: "expected", Results: []TestResult{{Status: "passed", Duration: 1200}}},
+							{ProjectName: "firefox", Status: "unexpected", Results: []TestResult{
+								{Status: "failed", Duration: 900, Errors: []PWError{{Message: "\x1b[31mError: expect(received).toBe(expected)\x1b[39m\n\nExpected: 2"}}},
+								{Status: "failed", Duration: 800, Retry: 1},
+							}},
+						},
+					},
+					{
+						Title: "removes item",
+						File:  "cart.spec.ts",
+						Line:  12,
+						Tests: []TestInstance{
+							{ProjectName: "chromium", Status: "flaky", Results: []TestResult{
+								{Status: "failed", Retry: 0},
+								{Status: "passed", Retry: 1, Duration: 300},
+							}},
+						},
+					},
+				},
+			}},
+		}},
+	}
+}
+
+func TestCollectResults(t *testing.T) {
+	results := collectResults(sampleReport())
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	failed := results[0]
+	if failed.status != statusFailed || failed.project != "firefox" {
+		t.Errorf("expected the firefox failure first, got %+v", failed)
+	}
+	if failed.title != "Cart › adds item" || failed.location() != "cart.spec.ts:4" {
+		t.Errorf("unexpected title or location: %q %q", failed.title, failed.location())
+	}
+	if failed.retries != 1 || failed.duration != 800*time.Millisecond {
+		t.Errorf("expected last attempt's retry and duration, got %d %s", failed.retries, failed.duration)
+	}
+	if failed.errMsg != "Error: expect(received).toBe(expected)" {
+		t.Errorf("expected first error line without ANSI codes, got %q", failed.errMsg)
+	}
+
+	if results[1].status != statusFlaky || results[2].status != statusPassed {
+		t.Errorf("expected flaky then passed, got %s then %s", results[1].status, results[2].status)
+	}
+}
+
+func TestRerunItems(t *testing.T) {
+	failed := []resultItem{
+		{title: "a", file: "a.spec.ts", line: 1, project: "chromium", status: statusFailed},
+		{title: "a", file: "a.spec.ts", line: 1, project: "firefox", status: statusFailed},
+		{title: "b", file: "b.spec.ts", line: 9, project: "chromium", status: statusFailed},
+	}
+
+	items := rerunItems(failed)
+	var tests, projects []string
+	for _, it := range items {
+		switch it.source {
+		case "Tests":
+			tests = append(tests, it.description)
+		case "Projects":
+			projects = append(projects, it.title)
+		}
+	}
+	if len(tests) != 2 || tests[0] != "a.spec.ts:1" || tests[1] != "b.spec.ts:9" {
+		t.Errorf("unexpected test items: %v", tests)
+	}
+	if len(projects) != 2 {
+		t.Errorf("expected 2 distinct projects, got %v", projects)
+	}
+}
+
+func TestRerunGroups(t *testing.T) {
+	failed := []resultItem{
+		{title: "a", file: "a.spec.ts", line: 1, project: "chromium", status: statusFailed},
+		{title: "b", file: "b.spec.ts", line: 9, project: "firefox", status: statusFailed},
+	}
+	groups := rerunGroups(failed)
+	if len(groups) != 2 {
+		t.Fatalf("expected a group per project, got %v", groups)
+	}
+	for i, want := range []string{"a.spec.ts:1 chromium", "b.spec.ts:9 firefox"} {
+		var got []string
+		for _, it := range groups[i] {
+			if it.source == "Tests" {
+				got = append(got, it.description)
+			} else {
+				got = append(got, it.title)
+			}
+		}
+		if strings.Join(got, " ") != want {
+			t.Errorf("group %d = %v; want %s", i, got, want)
+		}
+	}
+}
+
+func TestRerun_OneProjectAtATime(t *testing.T) {
+	isolateUserDirs(t)
+	m := NewModel(sampleReport(), nil, nil)
+	failed := []resultItem{
+		{title: "Cart › adds item", file: "cart.spec.ts", line: 4, project: "firefox", status: statusFailed},
+		{title: "Cart › removes item", file: "cart.spec.ts", line: 12, project: "chromium", status: statusFailed},
+	}
+	updated, cmd := m.rerun(rerunGroups(failed))
+	m = updated.(model)
+	if m.shards == nil || cmd == nil {
+		t.Fatalf("expected the failures in two projects to run as two parts")
+	}
+	if want := []string{"firefox", "chromium"}; strings.Join(m.shards.labels, " ") != strings.Join(want, " ") {
+		t.Errorf("labels = %v; want %v", m.shards.labels, want)
+	}
+	if len(m.shards.queued) != 1 {
+		t.Errorf("expected the second project to wait for the first, got %d queued", len(m.shards.queued))
+	}
+	first := strings.Join(m.runEntry.Runs[0].Args, " ")
+	if !strings.Contains(first, "cart.spec.ts:4 --project firefox") || strings.Contains(first, "cart.spec.ts:12") {
+		t.Errorf("expected only the firefox failure in the first part, got %q", first)
+	}
+
+	// The first part finishing starts the next
+	m.shards.runs[0] = &runState{stderr: &bytes.Buffer{}}
+	updated, cmd = m.Update(shardMsg{idx: 0, msg: runDoneMsg{}})
+	m = updated.(model)
+	if cmd == nil || len(m.shards.queued) != 0 {
+		t.Errorf("expected the queued part to start")
+	}
+}
+
+func TestReadResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	if err := os.WriteFile(path, []byte(`{"suites":[{"title":"a.spec.ts","file":"a.spec.ts","specs":[{"title":"t","file":"a.spec.ts","line":2,"tests":[{"projectName":"chromium","status":"expected","results":[{"status":"passed","duration":5,"retry":0}]}]}]}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := readResults(path)
+	if err != nil {
+		t.Fatalf("readResults failed: %v", err)
+	}
+	if got := report.Suites[0].Specs[0].Tests[0].Results[0].Duration; got != 5 {
+		t.Errorf("expected duration 5, got %d", got)
+	}
+
+	if _, err := readResults(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,402 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// runReporter is a Playwright reporter that streams test progress to stdout
+// as one JSON object per line, prefixed with runEventPrefix.
+const runReporter = `const path = require('path');
+
+const emit = (event) => process.stdout.write('` + runEventPrefix + `' + JSON.stringify(event) + '\n');
+
+class PwgoReporter {
+  onBegin(config, suite) {
+    this.rootDir = config.rootDir;
+    emit({
+      event: 'begin',
+      tests: suite.allTests().map((test) => ({
+        id: test.id,
+        title: test.titlePath().slice(3).join(' › '),
+        file: path.relative(this.rootDir, test.location.file),
+        line: test.location.line,
+        project: test.parent.project() ? test.parent.project().name : '',
+      })),
+    });
+  }
+
+  onTestBegin(test, result) {
+    emit({ event: 'testBegin', id: test.id, retry: result.retry });
+  }
+
+  onTestEnd(test, result) {
+    const failed = result.status !== 'passed' && result.status !== 'skipped';
+    emit({
+      event: 'testEnd',
+      id: test.id,
+      status: result.status,
+      outcome: test.outcome(),
+      retry: result.retry,
+      willRetry: failed && result.retry < test.retries,
+      duration: result.duration,
+    });
+  }
+
+  onEnd(result) {
+    emit({ event: 'end', status: result.status });
+  }
+
+  printsToStdio() {
+    return true;
+  }
+}
+
+module.exports = PwgoReporter;
+`
+
+const runEventPrefix = "PWGO "
+
+type runStatus string
+
+const (
+	statusQueued  runStatus = "queued"
+	statusRunning runStatus = "running"
+	statusPassed  runStatus = "passed"
+	statusFailed  runStatus = "failed"
+	statusFlaky   runStatus = "flaky"
+	statusSkipped runStatus = "skipped"
+)
+
+type runEvent struct {
+	Event     string    `json:"event"`
+	Tests     []runTest `json:"tests"`
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	Outcome   string    `json:"outcome"`
+	Retry     int       `json:"retry"`
+	WillRetry bool      `json:"willRetry"`
+	Duration  int64     `json:"duration"`
+}
+
+type runTest struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Project  string `json:"project"`
+	status   runStatus
+	retry    int
+	started  time.Time
+	duration time.Duration
+}
+
+// runState tracks an in-app Playwright run.
+type runState struct {
+	args     []string
+	cmd      *exec.Cmd
+	events   chan tea.Msg
+	tests    []*runTest
+	byID     map[string]*runTest
+	order    []*runTest // most recently finished first
+	started  time.Time
+	finished time.Time
+	done     bool
+	err      error
+	stderr   *bytes.Buffer
+	progress progress.Model
+}
+
+type runStartedMsg struct {
+	run *runState
+}
+
+type runEventMsg struct {
+	event runEvent
+}
+
+type runDoneMsg struct {
+	err    error
+	report *PlaywrightJSON
+}
+
+type runTickMsg struct{}
+
+type runFailedMsg struct {
+	err error
+}
+
+// stopGracePeriod is how long a stopped run has to shut down before it is
+// killed.
+const stopGracePeriod = 5 * time.Second
+
+// startRun launches Playwright with the streaming reporter and returns once
+// the process has started.
+func startRun(args, env []string) tea.Cmd {
+	return startRunIn("", args, env)
+}
+
+// startRunIn is startRun with Playwright running in workDir.
+func startRunIn(workDir string, args, env []string) tea.Cmd {
+	return func() tea.Msg {
+		dir, err := os.MkdirTemp("", "pwgo-run-")
+		if err != nil {
+			return runFailedMsg{fmt.Errorf("creating reporter directory: %w", err)}
+		}
+		reporter := filepath.Join(dir, "reporter.cjs")
+		if err := os.WriteFile(reporter, []byte(runReporter), 0o644); err != nil {
+			os.RemoveAll(dir)
+			return runFailedMsg{fmt.Errorf("writing reporter: %w", err)}
+		}
+
+		// The JSON reporter writes the full results to a file for the results screen
+		resultsPath := filepath.Join(dir, "results.json")
+		cmdArgs := append(append([]string{}, args...), "--reporter="+reporter+",json")
+		cmd := runCommand(cmdArgs, env)
+		runIn(cmd, workDir)
+		ownProcessGroup(cmd)
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env,
+			"PLAYWRIGHT_JSON_OUTPUT_NAME="+resultsPath,
+			"PLAYWRIGHT_JSON_OUTPUT_FILE="+resultsPath,
+		)
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			os.RemoveAll(dir)
+			return runFailedMsg{err}
+		}
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+
+		if err := cmd.Start(); err != nil {
+			os.RemoveAll(dir)
+			return runFailedMsg{fmt.Errorf("starting Playwright: %w", err)}
+		}
+
+		run := &runState{
+			args:     args,
+			cmd:      cmd,
+			events:   make(chan tea.Msg),
+			byID:     map[string]*runTest{},
+			started:  time.Now(),
+			stderr:   &stderr,
+			progress: progress.New(progress.WithDefaultGradient()),
+		}
+
+		go func() {
+			scanner := bufio.NewScanner(stdout)
+			scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+			for scanner.Scan() {
+				if ev, ok := parseRunEvent(scanner.Text()); ok {
+					run.events <- runEventMsg{ev}
+				}
+			}
+			err := cmd.Wait()
+			done := runDoneMsg{err: err}
+			if report, readErr := readResults(resultsPath); readErr == nil {
+				done.report = &report
+			}
+			os.RemoveAll(dir)
+			run.events <- done
+			close(run.events)
+		}()
+
+		return runStartedMsg{run}
+	}
+}
+
+func parseRunEvent(line string) (runEvent, bool) {
+	if !strings.HasPrefix(line, runEventPrefix) {
+		return runEvent{}, false
+	}
+	var ev runEvent
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, runEventPrefix)), &ev); err != nil {
+		return runEvent{}, false
+	}
+	return ev, true
+}
+
+func waitForRunMsg(events <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+func runTick() tea.Cmd {
+	return tea.Tick(200*time.Millisecond, func(time.Time) tea.Msg {
+		return runTickMsg{}
+	})
+}
+
+// apply updates the run from a reporter event.
+func (r *runState) apply(ev runEvent) {
+	switch ev.Event {
+	case "begin":
+		for i := range ev.Tests {
+			t := ev.Tests[i]
+			t.status = statusQueued
+			r.tests = append(r.tests, &t)
+			r.byID[t.ID] = &t
+		}
+	case "testBegin":
+		if t, ok := r.byID[ev.ID]; ok {
+			t.status = statusRunning
+			t.retry = ev.Retry
+			t.started = time.Now()
+		}
+	case "testEnd":
+		t, ok := r.byID[ev.ID]
+		if !ok {
+			return
+		}
+		t.duration = time.Duration(ev.Duration) * time.Millisecond
+		if ev.WillRetry {
+			t.status = statusQueued
+			return
+		}
+		switch ev.Outcome {
+		case "expected":
+			t.status = statusPassed
+		case "flaky":
+			t.status = statusFlaky
+		case "skipped":
+			t.status = statusSkipped
+		default:
+			t.status = statusFailed
+		}
+		r.order = append([]*runTest{t}, r.order...)
+	}
+}
+
+func (r *runState) counts() map[runStatus]int {
+	counts := map[runStatus]int{}
+	for _, t := range r.tests {
+		counts[t.status]++
+	}
+	return counts
+}
+
+// summary returns a one-line count of test outcomes.
+func (r *runState) summary() string {
+	counts := r.counts()
+	parts := []string{
+		runStatusStyle(statusPassed).Render(fmt.Sprintf("%d passed", counts[statusPassed])),
+		runStatusStyle(statusFailed).Render(fmt.Sprintf("%d failed", counts[statusFailed])),
+		runStatusStyle(statusFlaky).Render(fmt.Sprintf("%d flaky", counts[statusFlaky])),
+		runStatusStyle(statusSkipped).Render(fmt.Sprintf("%d skipped", counts[statusSkipped])),
+	}
+	if n := counts[statusRunning] + counts[statusQueued]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d remaining", n))
+	}
+	return strings.Join(parts, "  ")
+}
+
+func (r *runState) percent() float64 {
+	if len(r.tests) == 0 {
+		return 0
+	}
+	counts := r.counts()
+	finished := len(r.tests) - counts[statusQueued] - counts[statusRunning]
+	return float64(finished) / float64(len(r.tests))
+}
+
+func runStatusStyle(status runStatus) lipgloss.Style {
+	color := "8"
+	switch status {
+	case statusRunning:
+		color = "12"
+	case statusPassed:
+		color = "10"
+	case statusFailed:
+		color = "9"
+	case statusFlaky:
+		color = "11"
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+}
+
+var runStatusIcons = map[runStatus]string{
+	statusQueued:  "·",
+	statusRunning: "●",
+	statusPassed:  "✓",
+	statusFailed:  "✗",
+	statusFlaky:   "~",
+	statusSkipped: "○",
+}
+
+func (t *runTest) view(now time.Time) string {
+	elapsed := t.duration
+	if t.status == statusRunning {
+		elapsed = now.Sub(t.started)
+	}
+
+	line := fmt.Sprintf("%s %s", runStatusStyle(t.status).Render(runStatusIcons[t.status]), t.Title)
+	if t.Project != "" {
+		line += lipgloss.NewStyle().Faint(true).Render(" [" + t.Project + "]")
+	}
+	if t.retry > 0 {
+		line += runStatusStyle(statusFlaky).Render(fmt.Sprintf(" retry #%d", t.retry))
+	}
+	if t.status != statusQueued {
+		line += lipgloss.NewStyle().Faint(true).Render(" " + elapsed.Round(100*time.Millisecond).String())
+	}
+	return line
+}
+
+// testLines lists running tests first and then the most recently finished.
+func (r *runState) testLines(now time.Time) []string {
+	var lines []string
+	for _, t := range r.tests {
+		if t.status == statusRunning {
+			lines = append(lines, t.view(now))
+		}
+	}
+	for _, t := range r.order {
+		lines = append(lines, t.view(now))
+	}
+	if len(r.tests) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Faint(true).Render("Waiting for Playwright…"))
+	}
+	return lines
+}
+
+// view renders the live dashboard, showing running tests first and then the
+// most recently finished ones.
+func (r *runState) view(width, height int) string {
+	now := time.Now()
+	if r.done {
+		now = r.finished
+	}
+
+	var b strings.Builder
+	title := "Running tests"
+	if r.done {
+		title = "Run finished"
+	}
+	fmt.Fprintf(&b, "%s  %s\n\n", rootStyle.Render(title), lipgloss.NewStyle().Faint(true).Render(now.Sub(r.started).Round(time.Second).String()))
+
+	r.progress.Width = max(width-4, 10)
+	fmt.Fprintf(&b, "%s\n%s\n\n", r.progress.ViewAs(r.percent()), r.summary())
+
+	lines := r.testLines(now)
+	if room := height - 8; room > 0 && len(lines) > room {
+		lines = lines[:room]
+	}
+	b.WriteString(strings.Join(lines, "\n"))
+
+	b.WriteString("\n\n" + lipgloss.NewStyle().Faint(true).Render("esc/ctrl+c: stop run"))
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseRunEvent(t *testing.T) {
+	ev, ok := parseRunEvent(`PWGO {"event":"testEnd","id":"abc","status":"failed","outcome":"unexpected","duration":1500}`)
+	if !ok {
+		t.Fatalf("expected prefixed line to parse")
+	}
+	if ev.Event != "testEnd" || ev.ID != "abc" || ev.Duration != 1500 {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+
+	if _, ok := parseRunEvent("Running 3 tests using 2 workers"); ok {
+		t.Errorf("expected unprefixed output to be ignored")
+	}
+	if _, ok := parseRunEvent("PWGO {not json"); ok {
+		t.Errorf("expected malformed JSON to be ignored")
+	}
+}
+
+func TestRunState_Apply(t *testing.T) {
+	r := &runState{byID: map[string]*runTest{}}
+	r.apply(runEvent{Event: "begin", Tests: []runTest{
+		{ID: "a", Title: "passes"},
+		{ID: "b", Title: "flakes"},
+		{ID: "c", Title: "fails"},
+	}})
+
+	if r.percent() != 0 {
+		t.Errorf("expected 0%% before any test finished, got %v", r.percent())
+	}
+
+	r.apply(runEvent{Event: "testBegin", ID: "a"})
+	if r.byID["a"].status != statusRunning {
+		t.Errorf("expected a to be running, got %s", r.byID["a"].status)
+	}
+	r.apply(runEvent{Event: "testEnd", ID: "a", Outcome: "expected", Duration: 20})
+
+	r.apply(runEvent{Event: "testBegin", ID: "b"})
+	r.apply(runEvent{Event: "testEnd", ID: "b", Status: "failed", WillRetry: true})
+	if r.byID["b"].status != statusQueued {
+		t.Errorf("expected b to be queued for retry, got %s", r.byID["b"].status)
+	}
+	r.apply(runEvent{Event: "testBegin", ID: "b", Retry: 1})
+	r.apply(runEvent{Event: "testEnd", ID: "b", Outcome: "flaky", Retry: 1})
+
+	r.apply(runEvent{Event: "testBegin", ID: "c"})
+	r.apply(runEvent{Event: "testEnd", ID: "c", Outcome: "unexpected"})
+
+	counts := r.counts()
+	if counts[statusPassed] != 1 || counts[statusFlaky] != 1 || counts[statusFailed] != 1 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+	if r.percent() != 1 {
+		t.Errorf("expected 100%% once every test finished, got %v", r.percent())
+	}
+	if r.order[0].ID != "c" {
+		t.Errorf("expected most recently finished test first, got %s", r.order[0].ID)
+	}
+	if r.byID["b"].retry != 1 {
+		t.Errorf("expected retry count to be tracked, got %d", r.byID["b"].retry)
+	}
+}
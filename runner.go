@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const defaultRunner = "npx playwright"
+
+// runnerLockfiles maps package manager lockfiles to the command that runs the
+// locally installed Playwright CLI, in detection order.
+var runnerLockfiles = []struct {
+	file   string
+	runner string
+}{
+	{"pnpm-lock.yaml", "pnpm exec playwright"},
+	{"yarn.lock", "yarn playwright"},
+	{"bun.lockb", "bunx playwright"},
+	{"bun.lock", "bunx playwright"},
+	{"package-lock.json", "npx playwright"},
+}
+
+var envAssignment = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+
+// detectRunner picks a runner from the nearest lockfile above dir.
+func detectRunner(dir string) string {
+	for {
+		for _, lf := range runnerLockfiles {
+			if _, err := os.Stat(filepath.Join(dir, lf.file)); err == nil {
+				return lf.runner
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return defaultRunner
+		}
+		dir = parent
+	}
+}
+
+// splitCommandLine splits a runner template into words, honouring single
+// quotes, double quotes and backslash escapes.
+func splitCommandLine(s string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && strings.ContainsRune(`"\$`+"`", runes[i+1]):
+				i++
+				cur.WriteRune(runes[i])
+			default:
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '\\' && i+1 < len(runes):
+			i++
+			cur.WriteRune(runes[i])
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, s)
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
+
+// shellQuote quotes a word so a POSIX shell reads it back unchanged.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@%+=:,./-_", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func shellJoin(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = shellQuote(w)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// runnerArgv expands a runner template with the Playwright arguments. A
+// standalone {args} word is replaced by the arguments, {args} inside a word
+// (e.g. in `sh -c "..."`) by the shell-quoted arguments, and without any
+// placeholder the arguments are appended. Leading NAME=value words are
+// returned separately as environment variables.
+func runnerArgv(template string, args []string) (argv, env []string, err error) {
+	words, err := splitCommandLine(template)
+	if err != nil {
+		return nil, nil, err
+	}
+	for len(words) > 0 && envAssignment.MatchString(words[0]) {
+		env = append(env, words[0])
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return nil, nil, fmt.Errorf("runner %q has no command", template)
+	}
+
+	placeholder := false
+	for _, w := range words {
+		switch {
+		case w == "{args}":
+			argv = append(argv, args...)
+			placeholder = true
+		case strings.Contains(w, "{args}"):
+			argv = append(argv, strings.ReplaceAll(w, "{args}", shellJoin(args)))
+			placeholder = true
+		default:
+			argv = append(argv, w)
+		}
+	}
+	if !placeholder {
+		argv = append(argv, args...)
+	}
+	return argv, env, nil
+}
+
+// playwrightArgv expands the configured runner with the Playwright arguments.
+func playwrightArgv(args []string) (argv, env []string) {
+	runner := runnerCommand
+	if runner == "" {
+		runner = defaultRunner
+	}
+	argv, env, err := runnerArgv(runner, args)
+	if err != nil {
+		// The runner is validated at startup, so this only guards direct callers
+		argv, env, _ = runnerArgv(defaultRunner, args)
+	}
+	return argv, env
+}
+
+// playwrightCommand builds the command that runs Playwright with args using
+// the configured runner.
+func playwrightCommand(args []string) *exec.Cmd {
+	argv, env := playwrightArgv(args)
+	cmd := exec.Command(argv[0], argv[1:]...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd
+}
+
+// commandLine is the shell-quoted form of the command playwrightCommand runs,
+// with any runner environment variables in front.
+func commandLine(args []string) string {
+	argv, env := playwrightArgv(args)
+	return shellJoin(append(env, argv...))
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectRunner(t *testing.T) {
+	tests := []struct {
+		lockfile string
+		want     string
+	}{
+		{"pnpm-lock.yaml", "pnpm exec playwright"},
+		{"yarn.lock", "yarn playwright"},
+		{"bun.lockb", "bunx playwright"},
+		{"package-lock.json", "npx playwright"},
+	}
+
+	for _, tt := range tests {
+		root := t.TempDir()
+		nested := filepath.Join(root, "e2e")
+		os.MkdirAll(nested, 0o755)
+		os.WriteFile(filepath.Join(root, tt.lockfile), nil, 0o644)
+
+		if got := detectRunner(nested); got != tt.want {
+			t.Errorf("detectRunner with %s = %q; want %q", tt.lockfile, got, tt.want)
+		}
+	}
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	words, err := splitCommandLine(`docker compose exec "web app" sh -c 'npx playwright {args}' a\ b`)
+	if err != nil {
+		t.Fatalf("splitCommandLine failed: %v", err)
+	}
+	want := []string{"docker", "compose", "exec", "web app", "sh", "-c", "npx playwright {args}", "a b"}
+	if strings.Join(words, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q; want %q", words, want)
+	}
+
+	if _, err := splitCommandLine(`npx "playwright`); err == nil {
+		t.Errorf("expected an error for an unterminated quote")
+	}
+}
+
+func TestRunnerArgv(t *testing.T) {
+	args := []string{"test", "a.spec.ts:3", "--grep", "@smoke and more"}
+
+	argv, env, err := runnerArgv("pnpm exec playwright", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(argv, " "); got != "pnpm exec playwright test a.spec.ts:3 --grep @smoke and more" || len(env) != 0 {
+		t.Errorf("appended args: got %q env %v", got, env)
+	}
+
+	argv, env, err = runnerArgv("BASE_URL=http://localhost:3000 ./scripts/pw.sh {args} --trace on", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(env) != 1 || env[0] != "BASE_URL=http://localhost:3000" {
+		t.Errorf("expected leading assignment as env, got %v", env)
+	}
+	if argv[0] != "./scripts/pw.sh" || argv[len(argv)-1] != "on" || argv[4] != "@smoke and more" {
+		t.Errorf("expected {args} to be spliced in place, got %q", argv)
+	}
+
+	argv, _, err = runnerArgv(`docker compose exec web sh -c "npx playwright {args}"`, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := argv[len(argv)-1]; got != "npx playwright test a.spec.ts:3 --grep '@smoke and more'" {
+		t.Errorf("expected quoted args inside the word, got %q", got)
+	}
+
+	if _, _, err := runnerArgv("FOO=bar", args); err == nil {
+		t.Errorf("expected an error for a runner without a command")
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"a.spec.ts:12": "a.spec.ts:12",
+		"":             "''",
+		"@a and @b":    "'@a and @b'",
+		"it's":         `'it'\''s'`,
+	}
+	for in, want := range tests {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %q; want %q", in, got, want)
+		}
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	old := runnerCommand
+	defer func() { runnerCommand = old }()
+
+	runnerCommand = "CI=1 pnpm exec playwright"
+	got := commandLine([]string{"test", "a.spec.ts:3", "--grep", "@a and @b"})
+	want := "CI=1 pnpm exec playwright test a.spec.ts:3 --grep '@a and @b'"
+	if got != want {
+		t.Errorf("commandLine = %q; want %q", got, want)
+	}
+}
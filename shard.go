@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// maxShards keeps a typo from starting hundreds of browsers.
+const maxShards = 32
+
+// shardedRun is a selection split across Playwright processes, one per
+// --shard or, in monorepo mode, per config, or a re-run of failures made one
+// project at a time. Each part is an ordinary in-app run.
+type shardedRun struct {
+	title  string
+	labels []string
+	// prefixes namespace the file paths in each part's report
+	prefixes []string
+	runs     []*runState
+	failed   []error
+	reports  []*PlaywrightJSON
+	started  time.Time
+	// queued start the last parts one at a time, each once the part before
+	// has finished
+	queued []tea.Cmd
+	// stopping is set once the user stops the run, so parts still starting
+	// are killed as soon as they have a process
+	stopping bool
+}
+
+// shardMsg wraps a run message with the shard it belongs to.
+type shardMsg struct {
+	idx int
+	msg tea.Msg
+}
+
+func defaultShardCount() int {
+	return max(runtime.NumCPU()/4, 2)
+}
+
+// shardArgs adds a shard to args. Each shard writes to its own output
+// directory so they do not overwrite each other's artifacts, and unless the
+// workers were given the CPUs are split between the shards.
+func shardArgs(args []string, idx, total int) []string {
+	shard := append([]string{}, args...)
+	if !hasFlag(args, "--output", "-o") {
+		shard = append(shard, "--output", filepath.Join("test-results", fmt.Sprintf("shard-%d", idx+1)))
+	}
+	if !hasFlag(args, "--workers", "-j") {
+		shard = append(shard, fmt.Sprintf("--workers=%d", max(runtime.NumCPU()/total, 1)))
+	}
+	return append(shard, fmt.Sprintf("--shard=%d/%d", idx+1, total))
+}
+
+// hasFlag reports whether args set any of the flags, as "--flag value" or
+// "--flag=value".
+func hasFlag(args []string, flags ...string) bool {
+	for _, arg := range args {
+		for _, flag := range flags {
+			if arg == flag || strings.HasPrefix(arg, flag+"=") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newShardedRun(title string, labels []string) *shardedRun {
+	return &shardedRun{
+		title:    title,
+		labels:   labels,
+		prefixes: make([]string, len(labels)),
+		runs:     make([]*runState, len(labels)),
+		failed:   make([]error, len(labels)),
+		reports:  make([]*PlaywrightJSON, len(labels)),
+		started:  time.Now(),
+	}
+}
+
+// startPart starts one part of a sharded run with Playwright running in dir.
+func startPart(idx int, dir string, args, env []string) tea.Cmd {
+	start := startRunIn(dir, args, env)
+	return func() tea.Msg {
+		return shardMsg{idx: idx, msg: start()}
+	}
+}
+
+func waitForShardMsg(idx int, events <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return shardMsg{idx: idx, msg: <-events}
+	}
+}
+
+// startShardedRun launches the selection as the number of shards typed into
+// the prompt.
+func (m model) startShardedRun(value string) (tea.Model, tea.Cmd) {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 2 || n > maxShards {
+		return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle(fmt.Sprintf("Enter a number of shards from 2 to %d", maxShards)))
+	}
+	args, ok := m.runArgs()
+	if !ok {
+		return m, m.nothingToRun()
+	}
+
+	m.runEntry = m.newHistoryEntry(args)
+	labels := make([]string, n)
+	for i := range labels {
+		labels[i] = fmt.Sprintf("Shard %d/%d", i+1, n)
+	}
+	m.shards = newShardedRun(fmt.Sprintf("Running %d shards", n), labels)
+	cmds := []tea.Cmd{runTick()}
+	for i := 0; i < n; i++ {
+		cmds = append(cmds, startPart(i, "", shardArgs(args, i, n), m.runEntry.EnvVars))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m model) updateShard(msg shardMsg) (tea.Model, tea.Cmd) {
+	s := m.shards
+	if s == nil {
+		return m, nil
+	}
+	switch inner := msg.msg.(type) {
+	case runStartedMsg:
+		s.runs[msg.idx] = inner.run
+		if s.stopping {
+			stopProcessGroup(inner.run.cmd)
+		}
+		return m, waitForShardMsg(msg.idx, inner.run.events)
+	case runEventMsg:
+		s.runs[msg.idx].apply(inner.event)
+		return m, waitForShardMsg(msg.idx, s.runs[msg.idx].events)
+	case runDoneMsg:
+		r := s.runs[msg.idx]
+		r.done = true
+		r.finished = time.Now()
+		r.err = inner.err
+		s.reports[msg.idx] = inner.report
+	case runFailedMsg:
+		s.failed[msg.idx] = inner.err
+	}
+	if len(s.queued) > 0 {
+		next := s.queued[0]
+		s.queued = s.queued[1:]
+		return m, next
+	}
+	if !s.finished() {
+		return m, nil
+	}
+
+	// Finish as one run so history and the results screen work as usual
+	m.shards = nil
+	m.run = s.merged()
+	return m.finishRun(s.err(), s.report())
+}
+
+func (s *shardedRun) finished() bool {
+	for i, r := range s.runs {
+		if s.failed[i] == nil && (r == nil || !r.done) {
+			return false
+		}
+	}
+	return true
+}
+
+// stop kills every shard that is still running and drops the queued ones.
+// Shards still starting are killed when they report in.
+func (s *shardedRun) stop() {
+	s.stopping = true
+	for i := s.queuedFrom(); i < len(s.runs); i++ {
+		s.failed[i] = errors.New("stopped before it started")
+	}
+	s.queued = nil
+	for _, r := range s.runs {
+		if r != nil && !r.done {
+			stopProcessGroup(r.cmd)
+		}
+	}
+}
+
+// queuedFrom returns the index of the first part that is waiting its turn.
+func (s *shardedRun) queuedFrom() int {
+	return len(s.runs) - len(s.queued)
+}
+
+// merged combines the shards' tests and output into a single run.
+func (s *shardedRun) merged() *runState {
+	merged := &runState{started: s.started, stderr: &bytes.Buffer{}}
+	for i, r := range s.runs {
+		if s.failed[i] != nil {
+			fmt.Fprintln(merged.stderr, s.failed[i])
+			continue
+		}
+		if r != nil {
+			merged.tests = append(merged.tests, r.tests...)
+			merged.stderr.Write(r.stderr.Bytes())
+		}
+	}
+	return merged
+}
+
+// err returns the first shard failure, so the run counts as failed if any
+// shard did.
+func (s *shardedRun) err() error {
+	for i, r := range s.runs {
+		if s.failed[i] != nil {
+			return s.failed[i]
+		}
+		if r != nil && r.err != nil {
+			return r.err
+		}
+	}
+	return nil
+}
+
+func (s *shardedRun) report() *PlaywrightJSON {
+	var combined *PlaywrightJSON
+	for i, report := range s.reports {
+		if report == nil {
+			continue
+		}
+		if combined == nil {
+			combined = &PlaywrightJSON{Config: report.Config}
+		}
+		combined.Suites = append(combined.Suites, namespaceSuites(report.Suites, s.prefixes[i])...)
+		combined.Errors = append(combined.Errors, report.Errors...)
+	}
+	return combined
+}
+
+// view shows the combined summary above one pane per shard.
+func (s *shardedRun) view(width, height int) string {
+	now := time.Now()
+	faint := lipgloss.NewStyle().Faint(true)
+	total := s.merged()
+
+	lines := []string{
+		fmt.Sprintf("%s  %s", rootStyle.Render(s.title), faint.Render(now.Sub(s.started).Round(time.Second).String())),
+		"",
+		total.summary(),
+		"",
+	}
+
+	paneHeight := max((height-len(lines)-2)/len(s.runs), 2)
+	for i, r := range s.runs {
+		label := lipgloss.NewStyle().Bold(true).Render(s.labels[i])
+		switch {
+		case s.failed[i] != nil:
+			lines = append(lines, label+"  "+statusRemoveStyle(s.failed[i].Error()))
+			continue
+		case r == nil && i >= s.queuedFrom():
+			lines = append(lines, label+"  "+faint.Render("waiting…"))
+			continue
+		case r == nil:
+			lines = append(lines, label+"  "+faint.Render("starting…"))
+			continue
+		}
+
+		header := label + "  " + r.summary()
+		if r.done {
+			header += "  " + faint.Render(fmt.Sprintf("exit %d", exitCode(r.err)))
+		}
+		pane := []string{ansi.Truncate(header, width, "…")}
+		if paneHeight > 2 {
+			r.progress.Width = max(width-4, 10)
+			pane = append(pane, r.progress.ViewAs(r.percent()))
+		}
+		for _, line := range r.testLines(now) {
+			if len(pane) >= paneHeight {
+				break
+			}
+			pane = append(pane, "  "+ansi.Truncate(line, width-2, "…"))
+		}
+		lines = append(lines, pane...)
+	}
+
+	lines = append(lines, "", faint.Render("esc/ctrl+c: stop all"))
+	return strings.Join(lines, "\n")
+}
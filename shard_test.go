@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestShardArgs(t *testing.T) {
+	args := []string{"test", "cart.spec.ts"}
+	got := shardArgs(args, 1, 3)
+	workers := fmt.Sprintf("--workers=%d", max(runtime.NumCPU()/3, 1))
+	if want := []string{"test", "cart.spec.ts", "--output", filepath.Join("test-results", "shard-2"), workers, "--shard=2/3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("shardArgs = %v; want %v", got, want)
+	}
+	if len(args) != 2 {
+		t.Errorf("expected the original args to be left alone, got %v", args)
+	}
+
+	// Output and workers given by the user are kept
+	got = shardArgs([]string{"test", "--output=out", "-j", "2"}, 0, 2)
+	if want := []string{"test", "--output=out", "-j", "2", "--shard=1/2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("shardArgs = %v; want %v", got, want)
+	}
+}
+
+func TestShardedRun_FinishesAsOneRun(t *testing.T) {
+	isolateUserDirs(t)
+	m := NewModel(presetTestData(), nil, nil)
+	m.lists[m.listIdx("Tests")].Select(0)
+	args, _ := m.runArgs()
+	m.runEntry = m.newHistoryEntry(args)
+
+	newShard := func(title string) *runState {
+		return &runState{
+			byID:   map[string]*runTest{},
+			tests:  []*runTest{{Title: title, status: statusPassed}},
+			stderr: &bytes.Buffer{},
+		}
+	}
+	m.shards = newShardedRun("Running 2 shards", []string{"Shard 1/2", "Shard 2/2"})
+	m.shards.runs = []*runState{newShard("adds item"), newShard("removes item")}
+
+	first := sampleReport()
+	updated, _ := m.Update(shardMsg{idx: 0, msg: runDoneMsg{report: &first}})
+	m = updated.(model)
+	if m.shards == nil || m.showResults {
+		t.Fatalf("expected to wait for the other shard")
+	}
+
+	second := sampleReport()
+	exitErr := exec.Command("sh", "-c", "exit 1").Run()
+	updated, _ = m.Update(shardMsg{idx: 1, msg: runDoneMsg{err: exitErr, report: &second}})
+	m = updated.(model)
+	if m.shards != nil || m.run != nil {
+		t.Fatalf("expected the sharded run to finish")
+	}
+	if !m.showResults || len(m.results.Items()) != 2*len(collectResults(first)) {
+		t.Errorf("expected the results of both shards, got %d", len(m.results.Items()))
+	}
+	if m.lastRun == nil || m.lastRun.ExitCode != 1 {
+		t.Errorf("expected the run to be recorded as failed, got %+v", m.lastRun)
+	}
+}
+
+func TestShardedRun_FailedShard(t *testing.T) {
+	s := newShardedRun("Running 2 shards", []string{"Shard 1/2", "Shard 2/2"})
+	s.runs[0] = &runState{done: true, stderr: &bytes.Buffer{}}
+	s.failed[1] = errors.New("starting Playwright: no such file")
+	if !s.finished() {
+		t.Errorf("expected a shard that failed to start to count as finished")
+	}
+	if err := s.err(); err == nil || err != s.failed[1] {
+		t.Errorf("expected the failed shard's error, got %v", err)
+	}
+	if got := s.merged().stderr.String(); got != "starting Playwright: no such file\n" {
+		t.Errorf("expected the failure in the merged output, got %q", got)
+	}
+}
+
+func TestShardedRun_StopKillsShardsStillStarting(t *testing.T) {
+	isolateUserDirs(t)
+	m := NewModel(presetTestData(), nil, nil)
+	m.shards = newShardedRun("Running 2 shards", []string{"Shard 1/2", "Shard 2/2"})
+	m.shards.stop()
+
+	cmd := exec.Command("sleep", "30")
+	ownProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	m.Update(shardMsg{idx: 0, msg: runStartedMsg{&runState{cmd: cmd, events: make(chan tea.Msg)}}})
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		cmd.Process.Kill()
+		t.Errorf("expected a shard that started after stopping to be killed")
+	}
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tagExpr is a parsed tag query such as `(@checkout or @cart) and not @slow`.
+type tagExpr interface {
+	match(tags map[string]struct{}) bool
+	String() string
+}
+
+type tagTerm string
+
+type tagNot struct{ expr tagExpr }
+
+type tagAnd []tagExpr
+
+type tagOr []tagExpr
+
+func (t tagTerm) match(tags map[string]struct{}) bool {
+	_, ok := tags[string(t)]
+	return ok
+}
+
+func (n tagNot) match(tags map[string]struct{}) bool { return !n.expr.match(tags) }
+
+func (a tagAnd) match(tags map[string]struct{}) bool {
+	for _, e := range a {
+		if !e.match(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+func (o tagOr) match(tags map[string]struct{}) bool {
+	for _, e := range o {
+		if e.match(tags) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t tagTerm) String() string { return string(t) }
+
+func (n tagNot) String() string { return "not " + groupString(n.expr) }
+
+func (a tagAnd) String() string { return joinExprs(a, " and ") }
+
+func (o tagOr) String() string { return joinExprs(o, " or ") }
+
+func joinExprs(exprs []tagExpr, sep string) string {
+	parts := make([]string, len(exprs))
+	for i, e := range exprs {
+		parts[i] = groupString(e)
+	}
+	return strings.Join(parts, sep)
+}
+
+// groupString parenthesises compound operands so String round-trips.
+func groupString(e tagExpr) string {
+	switch e.(type) {
+	case tagAnd, tagOr:
+		return "(" + e.String() + ")"
+	}
+	return e.String()
+}
+
+// tokenizeTagQuery splits a query into tags, keywords and parentheses.
+// `&&`, `||` and `!` are accepted as shorthand for and, or and not.
+func tokenizeTagQuery(s string) ([]string, error) {
+	var tokens []string
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+		case r == '!':
+			tokens = append(tokens, "not")
+			i++
+		case strings.HasPrefix(string(runes[i:]), "&&"):
+			tokens = append(tokens, "and")
+			i += 2
+		case strings.HasPrefix(string(runes[i:]), "||"):
+			tokens = append(tokens, "or")
+			i += 2
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("()!&|", runes[i]) {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf("unexpected %q", r)
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		}
+	}
+	return tokens, nil
+}
+
+// parseTagQuery parses a boolean tag query. `not` binds tightest, then `and`,
+// then `or`.
+func parseTagQuery(s string) (tagExpr, error) {
+	tokens, err := tokenizeTagQuery(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty query")
+	}
+	p := &tagParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+type tagParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *tagParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return strings.ToLower(p.tokens[p.pos])
+	}
+	return ""
+}
+
+func (p *tagParser) parseOr() (tagExpr, error) {
+	var terms tagOr
+	for {
+		e, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, e)
+		if p.peek() != "or" {
+			break
+		}
+		p.pos++
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *tagParser) parseAnd() (tagExpr, error) {
+	var terms tagAnd
+	for {
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, e)
+		if p.peek() != "and" {
+			break
+		}
+		p.pos++
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *tagParser) parseUnary() (tagExpr, error) {
+	switch tok := p.peek(); tok {
+	case "":
+		return nil, fmt.Errorf("query ends unexpectedly")
+	case "not":
+		p.pos++
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return tagNot{e}, nil
+	case "(":
+		p.pos++
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return e, nil
+	case ")", "and", "or":
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	default:
+		tag := p.tokens[p.pos]
+		if !strings.HasPrefix(tag, "@") {
+			return nil, fmt.Errorf("tags start with @, got %q", tag)
+		}
+		p.pos++
+		return tagTerm(tag), nil
+	}
+}
+
+// matchTagQuery returns the specs whose tags satisfy the query.
+func matchTagQuery(expr tagExpr, specs []item) []item {
+	var matched []item
+	for _, s := range specs {
+		tags := map[string]struct{}{}
+		for _, t := range s.tags {
+			tags[t] = struct{}{}
+		}
+		if expr.match(tags) {
+			matched = append(matched, s)
+		}
+	}
+	return matched
+}
+
+// tagPattern matches a tag in a Playwright title without matching longer
+// tags that share its prefix, e.g. @smoke in "@smoke-extended".
+func tagPattern(tag string) string {
+	return regexp.QuoteMeta(tag) + `(?![\w-])`
+}
+
+// tagLookahead expresses any query as zero-width JavaScript lookaheads
+// anchored at the start of the title.
+func tagLookahead(e tagExpr) string {
+	switch e := e.(type) {
+	case tagTerm:
+		return "(?=.*" + tagPattern(string(e)) + ")"
+	case tagNot:
+		return "(?!" + tagLookahead(e.expr) + ")"
+	case tagAnd:
+		var b strings.Builder
+		for _, t := range e {
+			b.WriteString(tagLookahead(t))
+		}
+		return b.String()
+	case tagOr:
+		parts := make([]string, len(e))
+		for i, t := range e {
+			parts[i] = tagLookahead(t)
+		}
+		return "(?:" + strings.Join(parts, "|") + ")"
+	}
+	return ""
+}
+
+// tagAlternation returns `@a|@b` for a tag or an or of tags.
+func tagAlternation(e tagExpr) (string, bool) {
+	switch e := e.(type) {
+	case tagTerm:
+		return tagPattern(string(e)), true
+	case tagOr:
+		parts := make([]string, len(e))
+		for i, t := range e {
+			term, ok := t.(tagTerm)
+			if !ok {
+				return "", false
+			}
+			parts[i] = tagPattern(string(term))
+		}
+		return strings.Join(parts, "|"), true
+	}
+	return "", false
+}
+
+// tagGrep translates a query to Playwright's --grep and --grep-invert. Queries
+// of the form `A and not B` where A and B are tags or ors of tags keep the
+// readable pair; anything else becomes a single lookahead --grep.
+func tagGrep(e tagExpr) (grep, grepInvert string) {
+	conjuncts := []tagExpr{e}
+	if and, ok := e.(tagAnd); ok {
+		conjuncts = and
+	}
+
+	var include, exclude []string
+	for _, c := range conjuncts {
+		if not, ok := c.(tagNot); ok {
+			if alt, ok := tagAlternation(not.expr); ok {
+				exclude = append(exclude, alt)
+				continue
+			}
+		} else if alt, ok := tagAlternation(c); ok {
+			include = append(include, alt)
+			continue
+		}
+		return "^" + tagLookahead(e), ""
+	}
+	if len(include) > 1 {
+		return "^" + tagLookahead(e), ""
+	}
+	if len(include) == 1 {
+		grep = include[0]
+	}
+	return grep, strings.Join(exclude, "|")
+}
+
+// newQueryItem builds the Selected list entry for a tag query.
+func newQueryItem(expr tagExpr, specs []item) item {
+	desc := fmt.Sprintf("%d matching test%s", len(specs), plural(len(specs)))
+	if runnable := runnableCount(specs); runnable != len(specs) {
+		desc += fmt.Sprintf(" (%d runnable)", runnable)
+	}
+	return item{title: expr.String(), description: desc, source: "Queries", specs: specs}
+}
+
+// addTagQuery parses a query typed into the prompt and adds it to the
+// Selected list.
+func (m model) addTagQuery(query string) (tea.Model, tea.Cmd) {
+	expr, err := parseTagQuery(query)
+	if err != nil {
+		return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("Invalid tag query: " + err.Error()))
+	}
+	specs := matchTagQuery(expr, m.originalTests)
+	if len(specs) == 0 {
+		return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("No tests match " + expr.String()))
+	}
+
+	it := newQueryItem(expr, specs)
+	if _, ok := m.selectedKeys()[itemKey(it)]; !ok {
+		m.lists[m.selectedIdx()].InsertItem(len(m.lists[m.selectedIdx()].Items()), it)
+	}
+	return m, m.lists[m.focusedIdx].NewStatusMessage(statusSelectStyle(fmt.Sprintf("Selected query %s (%s)", it.title, it.description)))
+}
+
+// tagQueryStatus previews how many tests the query being typed matches.
+func (m model) tagQueryStatus(query string) string {
+	if strings.TrimSpace(query) == "" {
+		return "e.g. (@checkout or @cart) and not @slow"
+	}
+	expr, err := parseTagQuery(query)
+	if err != nil {
+		return statusRemoveStyle(err.Error())
+	}
+	return newQueryItem(expr, matchTagQuery(expr, m.originalTests)).description
+}
+
+// queryGrep decides whether the selected tag queries can run as --grep. That
+// is only safe when nothing but queries and projects is selected, since a
+// grep would also filter any tests, files or tags picked alongside them, and
+// when the listing was not filtered, since a grep would bring back tests the
+// filter left out.
+func (m model) queryGrep(items []item) (ok bool, grep, grepInvert string) {
+	var queries tagOr
+	for _, it := range items {
+		if it.stale {
+			continue
+		}
+		switch it.source {
+		case "Queries":
+			if it.excluded {
+				return false, "", ""
+			}
+			expr, err := parseTagQuery(it.title)
+			if err != nil {
+				return false, "", ""
+			}
+			queries = append(queries, expr)
+		case "Projects":
+		default:
+			return false, "", ""
+		}
+	}
+	if len(queries) == 0 {
+		return false, "", ""
+	}
+	for _, arg := range m.extraArgs {
+		if arg == "-g" || arg == "-gv" || strings.HasPrefix(arg, "--grep") {
+			return false, "", ""
+		}
+	}
+	if opts := currentListOptions; opts.grep != "" || opts.grepInvert != "" || opts.onlyChanged || opts.lastFailed {
+		return false, "", ""
+	}
+
+	var expr tagExpr = queries
+	if len(queries) == 1 {
+		expr = queries[0]
+	}
+	grep, grepInvert = tagGrep(expr)
+	return true, grep, grepInvert
+}
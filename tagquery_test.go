@@ -0,0 +1,143 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseTagQuery(t *testing.T) {
+	tests := map[string]string{
+		"@smoke":                              "@smoke",
+		"@smoke and not @slow":                "@smoke and not @slow",
+		"@a or @b and @c":                     "@a or (@b and @c)",
+		"(@checkout or @cart) and @mobile":    "(@checkout or @cart) and @mobile",
+		"!(@a || @b) && @c":                   "not (@a or @b) and @c",
+		"@smoke AND NOT @slow":                "@smoke and not @slow",
+		"not not @a":                          "not not @a",
+		"((@a))":                              "@a",
+		"@checkout or (@cart and not @flaky)": "@checkout or (@cart and not @flaky)",
+	}
+	for in, want := range tests {
+		expr, err := parseTagQuery(in)
+		if err != nil {
+			t.Errorf("parseTagQuery(%q) failed: %v", in, err)
+			continue
+		}
+		if got := expr.String(); got != want {
+			t.Errorf("parseTagQuery(%q) = %q; want %q", in, got, want)
+		}
+	}
+
+	for _, in := range []string{"", "@a and", "(@a or @b", "@a @b", "smoke", "and @a", "@a)"} {
+		if _, err := parseTagQuery(in); err == nil {
+			t.Errorf("expected an error for %q", in)
+		}
+	}
+}
+
+func TestMatchTagQuery(t *testing.T) {
+	specs := []item{
+		{title: "a", tags: []string{"@smoke"}},
+		{title: "b", tags: []string{"@smoke", "@slow"}},
+		{title: "c", tags: []string{"@cart", "@mobile"}},
+		{title: "d", tags: []string{"@checkout"}},
+	}
+	tests := map[string]string{
+		"@smoke and not @slow":             "a",
+		"(@checkout or @cart) and @mobile": "c",
+		"not @smoke":                       "c d",
+		"@smoke or @checkout":              "a b d",
+	}
+	for query, want := range tests {
+		expr, _ := parseTagQuery(query)
+		var got []string
+		for _, s := range matchTagQuery(expr, specs) {
+			got = append(got, s.title)
+		}
+		if strings.Join(got, " ") != want {
+			t.Errorf("%q matched %v; want %s", query, got, want)
+		}
+	}
+}
+
+func TestTagGrep(t *testing.T) {
+	tests := []struct {
+		query, grep, invert string
+	}{
+		{"@smoke", `@smoke(?![\w-])`, ""},
+		{"@smoke and not @slow", `@smoke(?![\w-])`, `@slow(?![\w-])`},
+		{"(@a or @b) and not (@c or @d)", `@a(?![\w-])|@b(?![\w-])`, `@c(?![\w-])|@d(?![\w-])`},
+		{"not @slow", "", `@slow(?![\w-])`},
+		{"@a and @b", `^(?=.*@a(?![\w-]))(?=.*@b(?![\w-]))`, ""},
+		{"(@a and @b) or @c", `^(?:(?=.*@a(?![\w-]))(?=.*@b(?![\w-]))|(?=.*@c(?![\w-])))`, ""},
+	}
+	for _, tt := range tests {
+		expr, err := parseTagQuery(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		grep, invert := tagGrep(expr)
+		if grep != tt.grep || invert != tt.invert {
+			t.Errorf("tagGrep(%q) = %q, %q; want %q, %q", tt.query, grep, invert, tt.grep, tt.invert)
+		}
+	}
+}
+
+func TestBuildArgs_Queries(t *testing.T) {
+	m := model{projects: []string{"chromium"}}
+	expr, _ := parseTagQuery("@smoke and not @slow")
+	query := newQueryItem(expr, []item{{source: "Tests", description: "a.spec.ts:3"}})
+
+	args := m.buildArgs([]item{query})
+	want := []string{"test", "--grep", `@smoke(?![\w-])`, "--grep-invert", `@slow(?![\w-])`, "--project", "chromium"}
+	if strings.Join(args, " ") != strings.Join(want, " ") {
+		t.Errorf("queries alone should run as grep:\n  got:  %v\n  want: %v", args, want)
+	}
+
+	args = m.buildArgs([]item{query, {source: "Tests", description: "b.spec.ts:9"}})
+	want = []string{"test", "a.spec.ts:3", "b.spec.ts:9", "--project", "chromium"}
+	if strings.Join(args, " ") != strings.Join(want, " ") {
+		t.Errorf("queries mixed with tests should expand to file:line:\n  got:  %v\n  want: %v", args, want)
+	}
+
+	m.extraArgs = []string{"--grep=@fast"}
+	args = m.buildArgs([]item{query})
+	want = []string{"test", "--grep=@fast", "a.spec.ts:3", "--project", "chromium"}
+	if strings.Join(args, " ") != strings.Join(want, " ") {
+		t.Errorf("an explicit --grep should not be overridden:\n  got:  %v\n  want: %v", args, want)
+	}
+
+	m.extraArgs = nil
+	defer func(opts listOptions) { currentListOptions = opts }(currentListOptions)
+	for _, opts := range []listOptions{{grep: "@fast"}, {grepInvert: "@flaky"}, {onlyChanged: true}, {lastFailed: true}} {
+		currentListOptions = opts
+		args = m.buildArgs([]item{query})
+		want = []string{"test", "a.spec.ts:3", "--project", "chromium"}
+		if strings.Join(args, " ") != strings.Join(want, " ") {
+			t.Errorf("a filtered listing %+v should expand queries to file:line:\n  got:  %v\n  want: %v", opts, args, want)
+		}
+	}
+}
+
+func TestModel_TagQueryPrompt(t *testing.T) {
+	isolateUserDirs(t)
+	m := NewModel(presetTestData(), nil, nil)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'@'}})
+	m = updated.(model)
+	if m.prompt != promptTagQuery {
+		t.Fatalf("expected the tag query prompt to open")
+	}
+	m.input.SetValue("not @smoke")
+	if got := m.tagQueryStatus(m.input.Value()); got != "1 matching test" {
+		t.Errorf("unexpected live preview: %q", got)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	selected := m.lists[m.selectedIdx()].Items()
+	if len(selected) != 1 || selected[0].(item).source != "Queries" || len(selected[0].(item).specs) != 1 {
+		t.Errorf("expected the query in the Selected list, got %+v", selected)
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var estimateStyle = lipgloss.NewStyle().Faint(true)
+
+// testTimings holds how long each test took in each project the last time
+// it ran in pwgo, in milliseconds, keyed by timingKey.
+type testTimings map[string]map[string]int64
+
+// runEstimate adds up the last known durations of some tests. untimed counts
+// the tests, once per project, that have never run in pwgo.
+type runEstimate struct {
+	total   time.Duration
+	timed   int
+	untimed int
+}
+
+func timingKey(file, title string) string {
+	return file + "|" + title
+}
+
+// timingsPath returns where timings are saved, keyed by the working directory
+// and config file so each project keeps its own.
+func timingsPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	cwd, _ := os.Getwd()
+	config := configPath
+	if config != "" {
+		config, _ = filepath.Abs(config)
+	}
+	if len(workspaceConfigs) > 0 {
+		config = strings.Join(workspaceConfigs, ",")
+	}
+	sum := sha256.Sum256([]byte(cwd + "\x00" + config))
+	return filepath.Join(dir, "pwgo", "timings-"+hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// readTimings loads the saved timings, starting afresh when there are none or
+// they cannot be read.
+func readTimings() testTimings {
+	timings := testTimings{}
+	path, err := timingsPath()
+	if err != nil {
+		return timings
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return timings
+	}
+	if err := json.Unmarshal(raw, &timings); err != nil || timings == nil {
+		return testTimings{}
+	}
+	return timings
+}
+
+func writeTimings(timings testTimings) error {
+	path, err := timingsPath()
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(timings)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, 0o644)
+}
+
+// record keeps the duration of the last attempt of every test in a run.
+// Skipped tests keep the timing they had.
+func (t testTimings) record(results []resultItem) {
+	for _, r := range results {
+		if r.status == statusSkipped {
+			continue
+		}
+		key := timingKey(r.file, r.title)
+		if t[key] == nil {
+			t[key] = map[string]int64{}
+		}
+		t[key][r.project] = r.duration.Milliseconds()
+	}
+}
+
+// estimate adds up the timings of specs in the given projects, or in every
+// project they run in when projects is empty. Skipped tests take no time.
+func (t testTimings) estimate(specs []item, projects []string) runEstimate {
+	var e runEstimate
+	for _, spec := range specs {
+		if spec.skipped {
+			continue
+		}
+		byProject := t[timingKey(specFile(spec), spec.title)]
+		for _, p := range spec.projects {
+			if len(projects) > 0 && !containsString(projects, p) {
+				continue
+			}
+			if ms, ok := byProject[p]; ok {
+				e.total += time.Duration(ms) * time.Millisecond
+				e.timed++
+			} else {
+				e.untimed++
+			}
+		}
+	}
+	return e
+}
+
+// String formats the estimate as "~1m5s", noting untimed tests. It is empty
+// when nothing has been timed.
+func (e runEstimate) String() string {
+	if e.timed == 0 {
+		return ""
+	}
+	s := "~" + formatEstimate(e.total)
+	if e.untimed > 0 {
+		s += fmt.Sprintf(" + %d untimed", e.untimed)
+	}
+	return s
+}
+
+func formatEstimate(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+// estimateTree sets the estimate shown on each test in the suite tree.
+func estimateTree(nodes []*treeNode, timings testTimings) {
+	for _, n := range nodes {
+		if n.spec != nil {
+			n.spec.estimate = timings.estimate([]item{*n.spec}, nil)
+		}
+		estimateTree(n.children, timings)
+	}
+}
+
+// selectionEstimate adds up the timings of the tests the Selected list runs,
+// in the projects it runs them in.
+func (m model) selectionEstimate() runEstimate {
+	items := listItems(m.lists[m.selectedIdx()])
+	locations, ok := m.selectionLocations(items)
+	if !ok {
+		for _, it := range items {
+			if !it.stale && it.source != "Projects" {
+				locations = append(locations, m.itemLocations(it)...)
+			}
+		}
+		if len(locations) == 0 {
+			// Only projects are selected, so every test runs
+			for _, it := range m.originalTests {
+				locations = append(locations, it.description)
+			}
+		}
+	}
+
+	byLocation := map[string]item{}
+	for _, it := range m.originalTests {
+		byLocation[it.description] = it
+	}
+	var specs []item
+	seen := map[string]struct{}{}
+	for _, loc := range locations {
+		it, ok := byLocation[loc]
+		if _, dup := seen[loc]; dup || !ok {
+			continue
+		}
+		seen[loc] = struct{}{}
+		specs = append(specs, it)
+	}
+	return m.timings.estimate(specs, m.runProjects(items))
+}
+
+// recordTimings remembers how long each test in a finished run took and
+// rebuilds the lists so their estimates include it.
+func (m *model) recordTimings(results []resultItem) {
+	if len(results) == 0 {
+		return
+	}
+	if m.timings == nil {
+		m.timings = testTimings{}
+	}
+	m.timings.record(results)
+	// Timings only feed the estimates, so failing to save them is not worth
+	// interrupting the results for
+	writeTimings(m.timings)
+	m.applyData(m.data)
+}
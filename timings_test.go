@@ -0,0 +1,85 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+func TestTestTimings_Estimate(t *testing.T) {
+	timings := testTimings{}
+	timings.record(collectResults(sampleReport()))
+
+	data := sampleReport()
+	data.Suites[0].Suites[0].Specs[1].Tests = append(data.Suites[0].Suites[0].Specs[1].Tests, TestInstance{ProjectName: "webkit"})
+	testList, fileList, _, _, _, fileToSpecs, _ := buildLists(data, timings)
+
+	// The last attempt counts, so the failed firefox retry takes 800ms
+	got := timings.estimate(fileToSpecs["cart.spec.ts"], nil)
+	if want := (runEstimate{total: 2300 * time.Millisecond, timed: 3, untimed: 1}); got != want {
+		t.Errorf("estimate = %+v; want %+v", got, want)
+	}
+	got = timings.estimate(fileToSpecs["cart.spec.ts"], []string{"chromium"})
+	if want := (runEstimate{total: 1500 * time.Millisecond, timed: 2}); got != want {
+		t.Errorf("chromium estimate = %+v; want %+v", got, want)
+	}
+
+	if desc := fileList.Items()[0].(item).Description(); desc != "~2.3s + 1 untimed" {
+		t.Errorf("expected the file's estimate in place of its count, got %q", desc)
+	}
+	_, untimed, _, _, _, _, _ := buildLists(data, testTimings{})
+	if desc := untimed.Items()[0].(item).Description(); desc != "6 tests across 3 projects" {
+		t.Errorf("expected the count while nothing has been timed, got %q", desc)
+	}
+	if title := testList.Items()[0].(item).Title(); !strings.Contains(title, "~2s") {
+		t.Errorf("expected the test's estimate in its title, got %q", title)
+	}
+}
+
+func TestRunEstimate_String(t *testing.T) {
+	for _, tc := range []struct {
+		estimate runEstimate
+		want     string
+	}{
+		{runEstimate{untimed: 2}, ""},
+		{runEstimate{total: 450 * time.Millisecond, timed: 1}, "~450ms"},
+		{runEstimate{total: 12345 * time.Millisecond, timed: 3}, "~12.3s"},
+		{runEstimate{total: 65400 * time.Millisecond, timed: 3, untimed: 2}, "~1m5s + 2 untimed"},
+	} {
+		if got := tc.estimate.String(); got != tc.want {
+			t.Errorf("%+v.String() = %q; want %q", tc.estimate, got, tc.want)
+		}
+	}
+}
+
+func TestSelectionEstimate(t *testing.T) {
+	isolateUserDirs(t)
+	m := NewModel(sampleReport(), nil, nil)
+	if got := m.originalTests[0].estimate; got.timed != 0 {
+		t.Fatalf("expected no timings before a run, got %+v", got)
+	}
+
+	m.recordTimings(collectResults(sampleReport()))
+	if got := m.originalTests[0].estimate.total; got != 2*time.Second {
+		t.Errorf("expected the lists to pick up the run's timings, got %s", got)
+	}
+	if saved := readTimings(); len(saved) != 2 {
+		t.Errorf("expected the timings to be saved, got %+v", saved)
+	}
+
+	// Selected projects narrow the estimate to their runs
+	m.lists[m.selectedIdx()].SetItems([]list.Item{m.originalFiles[0], item{source: "Projects", title: "chromium"}})
+	if got := m.selectionEstimate(); got.total != 1500*time.Millisecond || got.untimed != 0 {
+		t.Errorf("expected the chromium runs of the file, got %+v", got)
+	}
+
+	// Exclusions are taken out
+	removes := m.originalTests[1]
+	removes.excluded = true
+	m.lists[m.selectedIdx()].SetItems([]list.Item{m.originalFiles[0], removes})
+	if got := m.selectionEstimate(); got.total != 2*time.Second {
+		t.Errorf("expected the excluded test to be left out, got %+v", got)
+	}
+}
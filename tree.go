@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// treeNode is a file, describe block or test in the suite tree view.
+type treeNode struct {
+	title     string
+	fullTitle string
+	file      string
+	line      int
+	depth     int
+	spec      *item
+	fileItem  *item
+	children  []*treeNode
+}
+
+func buildTree(pwData PlaywrightJSON) []*treeNode {
+	var roots []*treeNode
+	byFile := map[string]*treeNode{}
+
+	for _, suite := range pwData.Suites {
+		file := suite.File
+		root, ok := byFile[file]
+		if !ok {
+			root = &treeNode{title: file, file: file}
+			byFile[file] = root
+			roots = append(roots, root)
+		}
+		addSuiteNodes(root, suite, "")
+	}
+
+	return roots
+}
+
+func addSuiteNodes(parent *treeNode, suite Suite, parentTitle string) {
+	fullTitle := suiteTitle(parentTitle, suite)
+
+	// File-level suites add their contents directly to the file node
+	target := parent
+	if fullTitle != parentTitle {
+		target = &treeNode{
+			title:     suite.Title,
+			fullTitle: fullTitle,
+			file:      suite.File,
+			line:      suite.Line,
+			depth:     parent.depth + 1,
+		}
+		parent.children = append(parent.children, target)
+	}
+
+	for _, spec := range suite.Specs {
+		testTitle := fullTitle
+		if testTitle != "" {
+			testTitle += " › "
+		}
+		testTitle += spec.Title
+
+		specItem := newSpecItem(spec, testTitle)
+		target.children = append(target.children, &treeNode{
+			title:     spec.Title,
+			fullTitle: testTitle,
+			file:      spec.File,
+			line:      spec.Line,
+			depth:     target.depth + 1,
+			spec:      &specItem,
+		})
+	}
+
+	for _, child := range suite.Suites {
+		addSuiteNodes(target, child, fullTitle)
+	}
+}
+
+// attachFileItems links file nodes to their Files list items so selecting a
+// file from the tree matches selecting it from the Files list.
+func attachFileItems(roots []*treeNode, files []item) {
+	for _, n := range roots {
+		for i := range files {
+			if files[i].title == n.file {
+				n.fileItem = &files[i]
+				break
+			}
+		}
+	}
+}
+
+// key uniquely identifies a node for tracking its collapsed state. Sibling
+// describe blocks can share a title, so the line tells them apart.
+func (n *treeNode) key() string {
+	return fmt.Sprintf("%s|%s|%d", n.file, n.fullTitle, n.line)
+}
+
+func (n *treeNode) isFile() bool {
+	return n.depth == 0
+}
+
+// specs returns every test beneath the node.
+func (n *treeNode) specs() []item {
+	if n.spec != nil {
+		return []item{*n.spec}
+	}
+	var specs []item
+	for _, child := range n.children {
+		specs = append(specs, child.specs()...)
+	}
+	return specs
+}
+
+// selectionItem converts the node into the item added to the Selected list.
+func (n *treeNode) selectionItem() item {
+	switch {
+	case n.spec != nil:
+		return *n.spec
+	case n.isFile():
+		if n.fileItem != nil {
+			return *n.fileItem
+		}
+		return item{title: n.file, source: "Files"}
+	default:
+		return item{
+			title:       n.fullTitle,
+			description: fmt.Sprintf("%s:%d", n.file, n.line),
+			line:        n.line,
+			source:      "Suites",
+			specs:       n.specs(),
+		}
+	}
+}
+
+// flattenTree renders the visible nodes as list items, skipping collapsed
+// children and nodes whose selection item is already selected.
+func flattenTree(nodes []*treeNode, collapsed map[string]bool, selected map[string]struct{}, hideSkipped bool) []list.Item {
+	var items []list.Item
+	for _, n := range nodes {
+		sel := n.selectionItem()
+		if _, ok := selected[itemKey(sel)]; ok {
+			continue
+		}
+		if hideSkipped && runnableCount(n.specs()) == 0 {
+			continue
+		}
+
+		marker := "•"
+		if n.spec == nil {
+			marker = "▾"
+			if collapsed[n.key()] {
+				marker = "▸"
+			}
+		}
+
+		treeItem := sel
+		treeItem.node = n
+		treeItem.label = fmt.Sprintf("%s%s %s", strings.Repeat("  ", n.depth), marker, n.title)
+		if n.spec == nil {
+			count := len(n.specs())
+			treeItem.description = fmt.Sprintf("%d test%s", count, plural(count))
+			if !n.isFile() {
+				treeItem.description += fmt.Sprintf(" · %s:%d", n.file, n.line)
+			}
+		}
+		items = append(items, treeItem)
+
+		if n.spec == nil && !collapsed[n.key()] {
+			items = append(items, flattenTree(n.children, collapsed, selected, hideSkipped)...)
+		}
+	}
+	return items
+}
+
+// itemKey identifies an item across lists regardless of how it is rendered.
+func itemKey(it item) string {
+	return it.source + "|" + it.title + "|" + it.description
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"testing"
+)
+
+func nestedSuiteData() PlaywrightJSON {
+	return PlaywrightJSON{
+		Suites: []Suite{{
+			Title: "checkout.spec.ts",
+			File:  "checkout.spec.ts",
+			Specs: []Spec{
+				{Title: "loads", File: "checkout.spec.ts", Line: 3, Tests: []TestInstance{{ProjectName: "chromium"}}},
+			},
+			Suites: []Suite{{
+				Title: "Cart",
+				File:  "checkout.spec.ts",
+				Line:  5,
+				Specs: []Spec{
+					{Title: "adds item", File: "checkout.spec.ts", Line: 6, Tests: []TestInstance{{ProjectName: "chromium"}}},
+				},
+				Suites: []Suite{{
+					Title: "Coupons",
+					File:  "checkout.spec.ts",
+					Line:  10,
+					Specs: []Spec{
+						{Title: "applies coupon", File: "checkout.spec.ts", Line: 11, Tests: []TestInstance{{ProjectName: "chromium"}}},
+					},
+				}},
+			}},
+		}},
+	}
+}
+
+func TestBuildTree_Structure(t *testing.T) {
+	roots := buildTree(nestedSuiteData())
+	if len(roots) != 1 {
+		t.Fatalf("expected 1 file node, got %d", len(roots))
+	}
+
+	file := roots[0]
+	if !file.isFile() || file.title != "checkout.spec.ts" {
+		t.Errorf("unexpected file node: %+v", file)
+	}
+	if len(file.children) != 2 {
+		t.Fatalf("expected test and describe under file, got %d children", len(file.children))
+	}
+
+	cart := file.children[1]
+	if cart.title != "Cart" || cart.depth != 1 {
+		t.Errorf("unexpected describe node: %+v", cart)
+	}
+	if len(cart.specs()) != 2 {
+		t.Errorf("expected 2 specs beneath Cart, got %d", len(cart.specs()))
+	}
+
+	coupon := cart.children[1].children[0]
+	if coupon.spec == nil || coupon.spec.title != "Cart › Coupons › applies coupon" {
+		t.Errorf("leaf title should match the flat Tests list, got %+v", coupon.spec)
+	}
+}
+
+func TestTreeNode_SelectionItem(t *testing.T) {
+	roots := buildTree(nestedSuiteData())
+	cart := roots[0].children[1]
+
+	sel := cart.selectionItem()
+	if sel.source != "Suites" || sel.title != "Cart" || sel.description != "checkout.spec.ts:5" {
+		t.Errorf("unexpected suite selection: %+v", sel)
+	}
+	if len(sel.specs) != 2 {
+		t.Errorf("expected suite selection to carry 2 specs, got %d", len(sel.specs))
+	}
+
+	files := []item{{title: "checkout.spec.ts", source: "Files", description: "3 tests across 1 project"}}
+	attachFileItems(roots, files)
+	if got := roots[0].selectionItem(); got.description != files[0].description {
+		t.Errorf("file node should select the Files list item, got %+v", got)
+	}
+}
+
+func TestFlattenTree_CollapseAndSelected(t *testing.T) {
+	roots := buildTree(nestedSuiteData())
+
+	all := flattenTree(roots, map[string]bool{}, map[string]struct{}{}, false)
+	if len(all) != 6 {
+		t.Fatalf("expected 6 visible nodes, got %d", len(all))
+	}
+
+	cart := roots[0].children[1]
+	collapsed := flattenTree(roots, map[string]bool{cart.key(): true}, map[string]struct{}{}, false)
+	if len(collapsed) != 3 {
+		t.Errorf("expected 3 visible nodes with Cart collapsed, got %d", len(collapsed))
+	}
+
+	selected := map[string]struct{}{itemKey(cart.selectionItem()): {}}
+	withoutCart := flattenTree(roots, map[string]bool{}, selected, false)
+	if len(withoutCart) != 2 {
+		t.Errorf("expected selected suite and its children hidden, got %d nodes", len(withoutCart))
+	}
+}
+
+func TestFlattenTree_CollapseSameTitledSiblings(t *testing.T) {
+	spec := func(title string, line int) Spec {
+		return Spec{Title: title, File: "a.spec.ts", Line: line}
+	}
+	roots := buildTree(PlaywrightJSON{Suites: []Suite{{
+		Title: "a.spec.ts", File: "a.spec.ts",
+		Suites: []Suite{
+			{Title: "group", File: "a.spec.ts", Line: 1, Specs: []Spec{spec("one", 2)}},
+			{Title: "group", File: "a.spec.ts", Line: 5, Specs: []Spec{spec("two", 6)}},
+		},
+	}}})
+
+	first := roots[0].children[0]
+	items := flattenTree(roots, map[string]bool{first.key(): true}, map[string]struct{}{}, false)
+	// The file, both groups and the test in the second one
+	if len(items) != 4 {
+		t.Errorf("expected only the first group collapsed, got %d visible nodes", len(items))
+	}
+}
@@ -2,17 +2,29 @@ package main
 
 import (
 	"fmt"
-	"os/exec"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 type keymap struct {
 	Submit, Remove, Select, ToggleRight, ToggleLeft key.Binding
+	TreeView, Fold, HideSkipped, Run                key.Binding
+	Rerun, RerunAll, AddFailed, Back                key.Binding
+	Load, SavePreset, Copy                          key.Binding
+	Preview, PreviewDown, PreviewUp, Edit           key.Binding
+	TagQuery, Exclude, Reload, EditGrep, Replay     key.Binding
+	Options, Shards, Env, DiffBase, DiffRef         key.Binding
 }
 
 type item struct {
@@ -21,21 +33,93 @@ type item struct {
 	line        int
 	source      string
 	tags        []string
+	annotations []string
+	skipped     bool
+	stale       bool
+	excluded    bool
+	specs       []item
+	node        *treeNode
+	label       string
+	// changed marks spec files and tests touched by the Changed list's diff
+	changed bool
+	// projects are the projects a test runs in
+	projects []string
+	// estimate is shown after the title of tests and in place of the count of
+	// files and tags
+	estimate runEstimate
+	// filter is matched by list filtering instead of the title when set
+	filter string
 }
 
 type model struct {
-	rightFocused  bool
-	tagToSpecs    map[string][]item
-	list          list.Model
-	lists         []list.Model
-	focusedIdx    int
-	quitting      bool
-	projects      []string
-	fileToSpecs   map[string][]item
-	extraArgs     []string
-	originalTests []item
-	originalFiles []item
-	originalTags  []item
+	rightFocused     bool
+	tagToSpecs       map[string][]item
+	list             list.Model
+	lists            []list.Model
+	focusedIdx       int
+	quitting         bool
+	projects         []string
+	fileToSpecs      map[string][]item
+	extraArgs        []string
+	originalTests    []item
+	originalFiles    []item
+	originalTags     []item
+	originalProjects []item
+	tree             []*treeNode
+	treeMode         bool
+	collapsed        map[string]bool
+	hideSkipped      bool
+	run              *runState
+	results          list.Model
+	showResults      bool
+	presets          map[string]savedSelection
+	prompt           promptKind
+	input            textinput.Model
+	width, height    int
+	// printCommand is written to stdout after the program exits in --print mode
+	printCommand  string
+	rootDir       string
+	preview       bool
+	previewCache  map[string]previewFile
+	previewFor    string
+	previewOffset int
+	watcher       *specWatcher
+	reloading     bool
+	reloadPending bool
+	// loading is set until the first --list finishes
+	loading bool
+	loadErr error
+	spinner spinner.Model
+	history []historyEntry
+	// runEntry is recorded in the history when the in-app run finishes
+	runEntry historyEntry
+	lastRun  *historyEntry
+	// options are the Playwright flags chosen in the options panel
+	options      []runOption
+	showOptions  bool
+	optionCursor int
+	optionsErr   string
+	shards       *shardedRun
+	// env is the env set from the config file that runs use
+	env string
+	// workspace holds the configs of a monorepo listing
+	workspace []workspaceConfig
+	// changeBase is what the Changed list compares with; diffRef is the last
+	// ref entered for it
+	changeBase      diffBase
+	diffRef         string
+	changes         map[string][]lineRange
+	changeSources   map[string][]byte
+	changesLabel    string
+	changesErr      error
+	originalChanged []item
+	// data is the listing the lists were built from, kept to rebuild them
+	// when new timings come in
+	data    PlaywrightJSON
+	timings testTimings
+	// selectionArgs are the extra arguments of presets and past runs loaded
+	// into the Selected list
+	selectionArgs []string
 }
 
 var keyMap = keymap{
@@ -44,6 +128,31 @@ var keyMap = keymap{
 	Select:      key.NewBinding(key.WithKeys("space"), key.WithHelp("space", "select")),
 	ToggleRight: key.NewBinding(key.WithKeys("L", "shift+right"), key.WithHelp("Shift+Right/L", "toggle right")),
 	ToggleLeft:  key.NewBinding(key.WithKeys("H", "shift+left"), key.WithHelp("Shift+Left/H", "toggle left")),
+	TreeView:    key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "tree view")),
+	Fold:        key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "expand/collapse")),
+	HideSkipped: key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "hide skipped")),
+	Run:         key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "run in pwgo")),
+	Rerun:       key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "re-run test")),
+	RerunAll:    key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "re-run failures")),
+	AddFailed:   key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "select failures")),
+	Back:        key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+	Load:        key.NewBinding(key.WithKeys("space"), key.WithHelp("space", "load")),
+	SavePreset:  key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "save preset")),
+	Copy:        key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy command")),
+	Preview:     key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "toggle preview")),
+	PreviewDown: key.NewBinding(key.WithKeys("ctrl+d"), key.WithHelp("ctrl+d", "scroll preview down")),
+	PreviewUp:   key.NewBinding(key.WithKeys("ctrl+u"), key.WithHelp("ctrl+u", "scroll preview up")),
+	Edit:        key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "open in editor")),
+	TagQuery:    key.NewBinding(key.WithKeys("@"), key.WithHelp("@", "tag query")),
+	Exclude:     key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "exclude")),
+	Reload:      key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "reload tests")),
+	EditGrep:    key.NewBinding(key.WithKeys("ctrl+g"), key.WithHelp("ctrl+g", "edit --grep")),
+	Replay:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "replay run")),
+	Options:     key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "run options")),
+	Shards:      key.NewBinding(key.WithKeys("P"), key.WithHelp("P", "run in shards")),
+	Env:         key.NewBinding(key.WithKeys("E"), key.WithHelp("E", "switch env")),
+	DiffBase:    key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "change diff base")),
+	DiffRef:     key.NewBinding(key.WithKeys("C"), key.WithHelp("C", "diff against ref")),
 }
 
 var appStyle = lipgloss.NewStyle().Padding(1, 2)
@@ -85,17 +194,29 @@ func reinsertInOriginalPosition(sel item, listModel *list.Model, original []item
 }
 
 func NewModel(pwData PlaywrightJSON, projects []string, extraArgs []string) model {
+	m := newModel(pwData, projects, extraArgs)
+	m.loadStartupPreset()
+	return m
+}
+
+func newModel(pwData PlaywrightJSON, projects []string, extraArgs []string) model {
 	selectedList := list.New([]list.Item{}, list.NewDefaultDelegate(), 40, 20)
 
 	selectedList.AdditionalShortHelpKeys = func() []key.Binding {
-		return []key.Binding{keyMap.Submit, keyMap.Remove}
+		return []key.Binding{keyMap.Submit, keyMap.Run, keyMap.Remove}
 	}
 	selectedList.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{keyMap.Submit, keyMap.Remove, keyMap.ToggleLeft, keyMap.ToggleRight}
+		return []key.Binding{keyMap.Submit, keyMap.Run, keyMap.Remove, keyMap.ToggleLeft, keyMap.ToggleRight, keyMap.SavePreset, keyMap.Copy, keyMap.Preview, keyMap.PreviewDown, keyMap.PreviewUp, keyMap.Edit, keyMap.Reload, keyMap.EditGrep, keyMap.Options, keyMap.Shards, keyMap.Env}
 	}
 	selectedList.Title = "Selected"
-	testList, fileList, tagList, tagToSpecs, fileToSpecs := buildLists(pwData)
-	lists := []list.Model{testList, fileList, tagList, selectedList}
+	timings := readTimings()
+	testList, fileList, tagList, projectList, tagToSpecs, fileToSpecs, _ := buildLists(pwData, timings)
+	presets, presetErr := loadPresets(presetsPath())
+	presetList := newPresetList(presets)
+	cwd, _ := os.Getwd()
+	history, historyErr := loadHistory(historyPath(), cwd)
+	historyList := newHistoryList(history)
+	lists := []list.Model{testList, fileList, tagList, projectList, newChangedList(), presetList, historyList, selectedList}
 	originalTests := make([]item, len(testList.Items()))
 	for i, it := range testList.Items() {
 		originalTests[i] = it.(item)
@@ -108,68 +229,436 @@ func NewModel(pwData PlaywrightJSON, projects []string, extraArgs []string) mode
 	for i, it := range tagList.Items() {
 		originalTags[i] = it.(item)
 	}
+	originalProjects := make([]item, len(projectList.Items()))
+	for i, it := range projectList.Items() {
+		originalProjects[i] = it.(item)
+	}
+
+	tree := buildTree(pwData)
+	attachFileItems(tree, originalFiles)
+	estimateTree(tree, timings)
 
 	for i := range lists {
 		lists[i].SetWidth(0)
 		lists[i].SetHeight(0)
 	}
 
-	return model{
-		lists:         lists,
-		focusedIdx:    0,
-		tagToSpecs:    tagToSpecs,
-		fileToSpecs:   fileToSpecs,
-		projects:      projects,
-		extraArgs:     extraArgs,
-		originalTests: originalTests,
-		originalFiles: originalFiles,
-		originalTags:  originalTags,
+	m := model{
+		lists:            lists,
+		focusedIdx:       0,
+		tagToSpecs:       tagToSpecs,
+		fileToSpecs:      fileToSpecs,
+		projects:         projects,
+		extraArgs:        extraArgs,
+		originalTests:    originalTests,
+		originalFiles:    originalFiles,
+		originalTags:     originalTags,
+		originalProjects: originalProjects,
+		tree:             tree,
+		collapsed:        map[string]bool{},
+		treeMode:         userConfig.UI.TreeView,
+		hideSkipped:      userConfig.UI.HideSkipped,
+		presets:          presets,
+		history:          history,
+		options:          defaultRunOptions(),
+		env:              envName,
+		workspace:        pwData.Workspace,
+		changeBase:       diffBase{kind: diffHead},
+		data:             pwData,
+		timings:          timings,
+		rootDir:          pwData.Config.RootDir,
+		preview:          true,
+		previewCache:     map[string]previewFile{},
+		spinner:          spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(spinnerStyle)),
+	}
+	if m.treeMode || m.hideSkipped {
+		m.refreshLists()
+	}
+
+	if presetErr != nil {
+		m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("Error loading presets: " + presetErr.Error()))
+	} else if historyErr != nil {
+		m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("Error loading run history: " + historyErr.Error()))
+	}
+	return m
+}
+
+// loadStartupPreset selects the preset named with --preset, if any.
+func (m *model) loadStartupPreset() tea.Cmd {
+	if _, ok := m.presets[presetName]; presetName == "" || !ok {
+		return nil
+	}
+	status := m.loadPreset(presetName)
+	m.focusedIdx = m.selectedIdx()
+	m.rightFocused = true
+	return m.lists[m.focusedIdx].NewStatusMessage(status)
+}
+
+// selectedIdx returns the index of the Selected list, which is always last.
+func (m model) selectedIdx() int {
+	return len(m.lists) - 1
+}
+
+// listIdx returns the index of the list with the given title, or -1.
+func (m model) listIdx(title string) int {
+	for i := range m.lists {
+		if m.lists[i].Title == title {
+			return i
+		}
 	}
+	return -1
+}
+
+func (m model) selectedKeys() map[string]struct{} {
+	keys := map[string]struct{}{}
+	for _, li := range m.lists[m.selectedIdx()].Items() {
+		keys[itemKey(li.(item))] = struct{}{}
+	}
+	return keys
+}
+
+// refreshTests rebuilds the Tests list as either the flat list or the suite
+// tree, leaving out anything already in the Selected list.
+func (m *model) refreshTests() {
+	idx := m.listIdx("Tests")
+	selected := m.selectedKeys()
+
+	var items []list.Item
+	if m.treeMode {
+		items = flattenTree(m.tree, m.collapsed, selected, m.hideSkipped)
+	} else {
+		items = m.visibleItems(m.originalTests, selected)
+	}
+	cursor := m.lists[idx].Index()
+	m.lists[idx].SetItems(items)
+	m.lists[idx].Select(min(cursor, max(len(items)-1, 0)))
+}
+
+// refreshLists rebuilds every list on the left from its original items.
+func (m *model) refreshLists() {
+	m.refreshTests()
+
+	selected := m.selectedKeys()
+	for title, original := range map[string][]item{
+		"Files":    m.originalFiles,
+		"Tags":     m.originalTags,
+		"Projects": m.originalProjects,
+		"Changed":  m.originalChanged,
+	} {
+		idx := m.listIdx(title)
+		items := m.visibleItems(original, selected)
+		cursor := m.lists[idx].Index()
+		m.lists[idx].SetItems(items)
+		m.lists[idx].Select(min(cursor, max(len(items)-1, 0)))
+	}
+}
+
+// visibleItems filters out selected items and, when hidden, skipped ones.
+func (m model) visibleItems(original []item, selected map[string]struct{}) []list.Item {
+	var items []list.Item
+	for _, it := range original {
+		if _, ok := selected[itemKey(it)]; ok {
+			continue
+		}
+		if m.hideSkipped && it.skipped {
+			continue
+		}
+		items = append(items, it)
+	}
+	return items
 }
 
 func (i item) Title() string {
+	title := i.title
+	if i.label != "" {
+		title = i.label
+	}
+	if i.changed {
+		title = changedStyle.Render("● ") + title
+	}
+	if i.excluded {
+		title = excludedStyle.Render("✕ " + title)
+	}
 	if i.source == "Tags" {
 		// Keep rendering tag styling for tag items
-		return fmt.Sprintf("%s  %s", i.title, tagStyleFor(i.title).Render(i.title))
+		return fmt.Sprintf("%s  %s", title, tagStyleFor(i.title).Render(i.title))
 	}
-	return i.title
+	if i.stale {
+		return fmt.Sprintf("%s  %s", title, statusRemoveStyle("stale"))
+	}
+	if len(i.annotations) > 0 {
+		var badges []string
+		for _, a := range i.annotations {
+			badges = append(badges, annotationStyleFor(a).Render(a))
+		}
+		title = fmt.Sprintf("%s  %s", title, strings.Join(badges, " "))
+	}
+	if estimate := i.estimate.String(); estimate != "" {
+		title += "  " + estimateStyle.Render(estimate)
+	}
+	return title
 }
 
 func (i item) Description() string {
+	description := i.description
+	// Once timed, files and tags show how long their tests take instead of
+	// how many there are
+	if estimate := i.estimate.String(); estimate != "" && i.source != "Tests" {
+		description = estimate
+	}
 	if len(i.tags) > 0 {
 		var styledTags []string
 		for _, tag := range i.tags {
 			styledTags = append(styledTags, tagStyleFor(tag).Render(tag))
 		}
-		return fmt.Sprintf("%s  %s", i.description, lipgloss.JoinHorizontal(lipgloss.Left, styledTags...))
+		return fmt.Sprintf("%s  %s", description, lipgloss.JoinHorizontal(lipgloss.Left, styledTags...))
 	}
-	return i.description
+	return description
 }
 
-func (i item) FilterValue() string { return i.title }
+func (i item) FilterValue() string {
+	if i.filter != "" {
+		return i.filter
+	}
+	return i.title
+}
 
-func (m model) Init() tea.Cmd { return nil }
+func (m model) Init() tea.Cmd {
+	if m.loading {
+		return tea.Batch(initialLoad(), m.spinner.Tick)
+	}
+	if m.watcher != nil {
+		return tea.Batch(waitForChange(m.watcher.changes), m.loadChanges())
+	}
+	return m.loadChanges()
+}
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	if next, ok := updated.(model); ok {
+		next.refreshPreview()
+		return next, cmd
+	}
+	return updated, cmd
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		h, v := appStyle.GetFrameSize()
-		for i := range m.lists {
-			m.lists[i].SetSize(msg.Width-h, msg.Height-v)
+		m.width, m.height = msg.Width-h, msg.Height-v
+		m.resize()
+	case runStartedMsg:
+		m.run = msg.run
+		return m, tea.Batch(waitForRunMsg(m.run.events), runTick())
+	case runEventMsg:
+		if m.run == nil {
+			return m, nil
+		}
+		m.run.apply(msg.event)
+		return m, waitForRunMsg(m.run.events)
+	case runTickMsg:
+		if (m.run != nil && !m.run.done) || m.shards != nil {
+			return m, runTick()
+		}
+		return m, nil
+	case shardMsg:
+		return m.updateShard(msg)
+	case runDoneMsg:
+		if m.run == nil {
+			return m, nil
+		}
+		return m.finishRun(msg.err, msg.report)
+	case runFailedMsg:
+		return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle(msg.err.Error()))
+	case dataChangedMsg:
+		return m.handleDataChanged()
+	case dataReloadedMsg:
+		return m.handleDataReloaded(msg)
+	case cachedDataMsg:
+		return m.handleCachedData(msg)
+	case changesMsg:
+		return m.handleChanges(msg)
+	case execFinishedMsg:
+		return m.handleExecFinished(msg)
+	case spinner.TickMsg:
+		if !m.reloading {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case editorFinishedMsg:
+		// The file may have changed, so highlight it again next time
+		delete(m.previewCache, msg.path)
+		if msg.err != nil {
+			return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("Editor failed: " + msg.err.Error()))
 		}
+		return m, nil
 	case tea.KeyMsg:
+		if m.shards != nil {
+			switch msg.String() {
+			case "esc", "ctrl+c":
+				m.shards.stop()
+			}
+			return m, nil
+		}
+		if m.run != nil {
+			switch msg.String() {
+			case "esc", "ctrl+c":
+				stopProcessGroup(m.run.cmd)
+			}
+			return m, nil
+		}
+		if m.showResults {
+			return m.updateResults(msg)
+		}
+		if m.prompt != promptNone {
+			return m.updatePrompt(msg)
+		}
+		if m.loading || m.loadErr != nil {
+			return m.updateLoading(msg)
+		}
+		if m.showOptions {
+			return m.updateOptions(msg)
+		}
+
 		switch msg.String() {
 		case "L", "shift+right":
 			if m.lists[m.focusedIdx].FilterState() != list.Filtering {
 				m.lists[m.focusedIdx].NewStatusMessage("")
 				m.focusedIdx = (m.focusedIdx + 1) % len(m.lists)
-				m.rightFocused = m.focusedIdx == 3
+				m.rightFocused = m.focusedIdx == m.selectedIdx()
 			}
 		case "H", "shift+left":
 			if m.lists[m.focusedIdx].FilterState() != list.Filtering {
 				m.lists[m.focusedIdx].NewStatusMessage("")
 				m.focusedIdx = (m.focusedIdx + len(m.lists) - 1) % len(m.lists)
-				m.rightFocused = m.focusedIdx == 3
+				m.rightFocused = m.focusedIdx == m.selectedIdx()
+			}
+		case "t":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering && m.lists[m.focusedIdx].Title == "Tests" {
+				m.treeMode = !m.treeMode
+				m.lists[m.focusedIdx].ResetFilter()
+				m.lists[m.focusedIdx].Select(0)
+				m.refreshTests()
+				mode := "flat view"
+				if m.treeMode {
+					mode = "tree view"
+				}
+				return m, m.lists[m.focusedIdx].NewStatusMessage(statusSelectStyle("Switched to " + mode))
+			}
+		case "tab":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering && m.treeMode && m.lists[m.focusedIdx].Title == "Tests" {
+				selectedItem := m.lists[m.focusedIdx].SelectedItem()
+				if selectedItem == nil {
+					break
+				}
+				if node := selectedItem.(item).node; node != nil && node.spec == nil {
+					m.collapsed[node.key()] = !m.collapsed[node.key()]
+					m.refreshTests()
+				}
+				return m, nil
+			}
+		case "s":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering && m.focusedIdx != m.selectedIdx() {
+				m.hideSkipped = !m.hideSkipped
+				m.lists[m.focusedIdx].ResetFilter()
+				m.refreshLists()
+				status := "Showing skipped tests"
+				if m.hideSkipped {
+					status = "Hiding skipped tests"
+				}
+				return m, m.lists[m.focusedIdx].NewStatusMessage(statusSelectStyle(status))
+			}
+		case "r":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering {
+				if len(m.lists[m.focusedIdx].Items()) == 0 && len(m.lists[m.selectedIdx()].Items()) == 0 {
+					msg := statusRemoveStyle("No items selected to run")
+					return m, m.lists[m.focusedIdx].NewStatusMessage(msg)
+				}
+				if flag, ok := m.execOnlyFlag(); ok {
+					return m, m.lists[m.focusedIdx].NewStatusMessage(inAppRunRefused(flag))
+				}
+				if runs, ok := m.configRuns(); ok {
+					return m.startConfigRuns(runs, m.newHistoryEntry(nil))
+				}
+				args, ok := m.runArgs()
+				if !ok {
+					return m, m.nothingToRun()
+				}
+				m.runEntry = m.newHistoryEntry(args)
+				return m, startRun(args, m.runEntry.EnvVars)
+			}
+		case "E":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering {
+				return m, m.cycleEnv()
+			}
+		case "c":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering && m.lists[m.focusedIdx].Title == "Changed" {
+				return m, m.setDiffBase(m.changeBase.next(m.diffRef))
+			}
+		case "C":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering && m.lists[m.focusedIdx].Title == "Changed" {
+				return m, m.openPrompt(promptDiffRef, "Diff against:", m.diffRef)
+			}
+		case "P":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering {
+				if len(m.lists[m.focusedIdx].Items()) == 0 && len(m.lists[m.selectedIdx()].Items()) == 0 {
+					return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("No items selected to run"))
+				}
+				if flag, ok := m.execOnlyFlag(); ok {
+					return m, m.lists[m.focusedIdx].NewStatusMessage(inAppRunRefused(flag))
+				}
+				if _, ok := m.configRuns(); ok {
+					return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("Sharding is not available for runs across several configs"))
+				}
+				return m, m.openPrompt(promptShards, "Shards:", strconv.Itoa(defaultShardCount()))
+			}
+		case "p":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering {
+				m.preview = !m.preview
+				m.resize()
+				return m, nil
+			}
+		case "e":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering {
+				return m.openInEditor()
+			}
+		case "ctrl+d", "ctrl+u":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering && m.previewVisible() {
+				delta := max(m.height/2, 1)
+				if msg.String() == "ctrl+u" {
+					delta = -delta
+				}
+				m.scrollPreview(delta)
+				return m, nil
+			}
+		case "x":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering {
+				return m.toggleExclude()
+			}
+		case "@":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering {
+				return m, m.openPrompt(promptTagQuery, "Tag query:", "")
+			}
+		case "o":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering {
+				m.showOptions = true
+				return m, nil
+			}
+		case "ctrl+r":
+			return m, m.startReload()
+		case "ctrl+g":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering {
+				return m, m.openPrompt(promptGrep, "--grep:", currentListOptions.grep)
+			}
+		case "ctrl+s":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering {
+				if len(m.lists[m.selectedIdx()].Items()) == 0 {
+					return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("Select items to save as a preset"))
+				}
+				return m, m.openPrompt(promptPresetName, "Preset name:", "")
 			}
 		case "ctrl+c", "q":
 			if m.lists[m.focusedIdx].FilterState() != list.Filtering {
@@ -181,118 +670,54 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					msg := statusRemoveStyle("No items selected to submit")
 					return m, m.lists[m.focusedIdx].NewStatusMessage(msg)
 				}
-				// If no items selected on right, and enter pressed on left list, run that single item
-				if len(m.lists[3].Items()) == 0 && m.focusedIdx != 3 {
-					selectedItem := m.lists[m.focusedIdx].SelectedItem()
-					if selectedItem == nil {
-						break
-					}
-					args := []string{"playwright", "test"}
-					if configPath != "" {
-						args = append(args, "--config", configPath)
-					}
-					it := selectedItem.(item)
-					args = append(args, m.extraArgs...)
-
-					if specs, ok := m.tagToSpecs[it.title]; ok && it.source == "Tags" {
-						seen := map[string]struct{}{}
-						for _, specItem := range specs {
-							arg := specItem.description // "file:line"
-							if _, exists := seen[arg]; !exists {
-								args = append(args, arg)
-								seen[arg] = struct{}{}
-							}
-						}
-					} else {
-						// Normal case for Tests or Files
-						var arg string
-						switch it.source {
-						case "Tests":
-							arg = it.description // file:line
-						case "Files":
-							arg = it.title
-						default:
-							arg = it.title
-						}
-						args = append(args, m.extraArgs...)
-						args = append(args, arg)
-					}
-
-					for _, p := range m.projects {
-						args = append(args, "--project", p)
-					}
-
-					m.quitting = true
-					return m, tea.ExecProcess(exec.Command("npx", args...), nil)
+				if runs, ok := m.configRuns(); ok {
+					return m.execConfigRuns(runs)
 				}
-
-				// Else fallback: use the selected list as before
-				args := []string{"playwright", "test"}
-				seen := map[string]struct{}{}
-				var projects []string
-				args = append(args, m.extraArgs...)
-
-				for _, li := range m.lists[3].Items() {
-					it := li.(item)
-
-					// Handle tag item properly by expanding to matching tests
-					if it.source == "Tags" {
-						if specs, ok := m.tagToSpecs[it.title]; ok {
-							for _, specItem := range specs {
-								arg := specItem.description // "file:line"
-								if _, exists := seen[arg]; !exists {
-									args = append(args, arg)
-									seen[arg] = struct{}{}
-								}
-							}
-						}
-						continue
-					}
-
-					// Handle project-only selection
-					if it.source == "Projects" {
-						projects = append(projects, it.title)
-						continue
-					}
-
-					// Handle file or test
-					var arg string
-					switch it.source {
-					case "Tests":
-						arg = it.description // file:line
-					case "Files":
-						arg = it.title
-					default:
-						arg = it.title
-					}
-					if _, exists := seen[arg]; !exists {
-						args = append(args, arg)
-						seen[arg] = struct{}{}
-					}
-				}
-
-				for _, p := range m.projects {
-					args = append(args, "--project", p)
+				args, ok := m.runArgs()
+				if !ok {
+					return m, m.nothingToRun()
 				}
 
 				m.quitting = true
-				return m, tea.ExecProcess(exec.Command("npx", args...), nil)
+				entry := m.newHistoryEntry(args)
+				if printOnly {
+					m.printCommand = runCommandLine(args, entry.EnvVars)
+					return m, tea.Quit
+				}
+				return m, tea.ExecProcess(runCommand(args, entry.EnvVars), func(err error) tea.Msg {
+					return execFinishedMsg{entry: entry, err: err}
+				})
+			}
+		case "y":
+			if m.lists[m.focusedIdx].FilterState() != list.Filtering {
+				if len(m.lists[m.focusedIdx].Items()) == 0 && len(m.lists[m.selectedIdx()].Items()) == 0 {
+					return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("No items selected to copy"))
+				}
+				command, ok := m.shownCommandLine()
+				if !ok {
+					return m, m.nothingToRun()
+				}
+				if err := clipboard.WriteAll(command); err != nil {
+					return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("Error copying command: " + err.Error()))
+				}
+				return m, m.lists[m.focusedIdx].NewStatusMessage(statusSelectStyle("Copied: " + command))
 			}
 		case " ":
 			if m.lists[m.focusedIdx].FilterState() != list.Filtering {
 				if m.rightFocused {
 					// Remove from selected list and re-add to left
-					selectedItem := m.lists[3].SelectedItem()
+					selectedItem := m.lists[m.selectedIdx()].SelectedItem()
 					if selectedItem == nil {
 						break
 					}
 					var updated []list.Item
-					for _, it := range m.lists[3].Items() {
-						if it.FilterValue() != selectedItem.FilterValue() {
+					for _, it := range m.lists[m.selectedIdx()].Items() {
+						if itemKey(it.(item)) != itemKey(selectedItem.(item)) {
 							updated = append(updated, it)
 						} else {
 							// Put back into matching left list
 							sel := selectedItem.(item)
+							sel.excluded = false
 							// Determine which original slice to use
 							var original []item
 							switch sel.source {
@@ -302,52 +727,89 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 								original = m.originalFiles
 							case "Tags":
 								original = m.originalTags
+							case "Projects":
+								original = m.originalProjects
 							default:
 								break
 							}
 
 							// Call reinsertion once on the correct list
 							for i := range m.lists {
-								if m.lists[i].Title == sel.source {
+								if m.lists[i].Title == sel.source && !sel.stale {
 									reinsertInOriginalPosition(sel, &m.lists[i], original)
 									break
 								}
 							}
 						}
 					}
-					m.lists[3].SetItems(updated)
+					m.lists[m.selectedIdx()].SetItems(updated)
+					if m.treeMode || m.hideSkipped || len(m.originalChanged) > 0 {
+						m.refreshLists()
+					}
 
 					// Reset filtering
 					m.lists[m.focusedIdx].ResetFilter()
 
-					removedMsg := fmt.Sprintf("Removed %s", strings.ToLower(selectedItem.(item).source[:len(selectedItem.(item).source)-1]))
-					return m, m.lists[3].NewStatusMessage(statusRemoveStyle(removedMsg))
+					removedMsg := fmt.Sprintf("Removed %s", sourceNoun(selectedItem.(item).source))
+					return m, m.lists[m.selectedIdx()].NewStatusMessage(statusRemoveStyle(removedMsg))
 				} else {
 					// Add to selected list and remove from left list
 					selectedItem := m.lists[m.focusedIdx].SelectedItem()
 					if selectedItem == nil {
 						break
 					}
-					for _, it := range m.lists[3].Items() {
-						if it.FilterValue() == selectedItem.FilterValue() {
+					if selectedItem.(item).source == "Presets" {
+						status := m.loadPreset(selectedItem.(item).title)
+						return m, m.lists[m.focusedIdx].NewStatusMessage(status)
+					}
+					if past, ok := m.highlightedHistory(); ok {
+						status := m.loadHistorySelection(past)
+						return m, m.lists[m.focusedIdx].NewStatusMessage(status)
+					}
+					if node := selectedItem.(item).node; node != nil {
+						// Tree nodes select the equivalent test, file or suite
+						sel := node.selectionItem()
+						if _, ok := m.selectedKeys()[itemKey(sel)]; ok {
 							return m, nil // already selected
 						}
+						m.lists[m.selectedIdx()].InsertItem(len(m.lists[m.selectedIdx()].Items()), sel)
+						if idx := m.listIdx(sel.source); idx >= 0 && sel.source != "Tests" {
+							var newItems []list.Item
+							for _, it := range m.lists[idx].Items() {
+								if itemKey(it.(item)) != itemKey(sel) {
+									newItems = append(newItems, it)
+								}
+							}
+							m.lists[idx].SetItems(newItems)
+						}
+						m.lists[m.focusedIdx].ResetFilter()
+						m.refreshTests()
+
+						addedMsg := fmt.Sprintf("Selected %s", sourceNoun(sel.source))
+						return m, m.lists[m.focusedIdx].NewStatusMessage(statusSelectStyle(addedMsg))
 					}
-					m.lists[3].InsertItem(len(m.lists[3].Items()), selectedItem)
+					if _, ok := m.selectedKeys()[itemKey(selectedItem.(item))]; ok {
+						return m, nil // already selected
+					}
+					m.lists[m.selectedIdx()].InsertItem(len(m.lists[m.selectedIdx()].Items()), selectedItem)
 
 					// Remove from the left list
 					var newItems []list.Item
 					for _, it := range m.lists[m.focusedIdx].Items() {
-						if it.FilterValue() != selectedItem.FilterValue() {
+						if itemKey(it.(item)) != itemKey(selectedItem.(item)) {
 							newItems = append(newItems, it)
 						}
 					}
 					m.lists[m.focusedIdx].SetItems(newItems)
+					if len(m.originalChanged) > 0 {
+						// The same test or file can also be on the Changed list
+						m.refreshLists()
+					}
 
 					// Reset filtering
 					m.lists[m.focusedIdx].ResetFilter()
 
-					addedMsg := fmt.Sprintf("Selected %s", strings.ToLower(selectedItem.(item).source[:len(selectedItem.(item).source)-1]))
+					addedMsg := fmt.Sprintf("Selected %s", sourceNoun(selectedItem.(item).source))
 					return m, m.lists[m.focusedIdx].NewStatusMessage(statusSelectStyle(addedMsg))
 				}
 			}
@@ -358,11 +820,363 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, commd
 }
 
+// runArgs builds the Playwright arguments for the Selected list or, when
+// nothing has been selected, for the highlighted item on the focused list.
+// A past run highlighted on the History list is replayed as it was.
+func (m model) runArgs() ([]string, bool) {
+	if past, ok := m.highlightedHistory(); ok {
+		return past.Args, true
+	}
+	items, extraArgs := m.runItems()
+	if len(items) == 0 || m.leavesNothingToRun(items) {
+		return nil, false
+	}
+	m.extraArgs = extraArgs
+	return m.buildArgs(items), true
+}
+
+// runItems returns the items a run covers and the extra arguments to run
+// them with, which include those saved with a highlighted preset.
+func (m model) runItems() ([]item, []string) {
+	var items []item
+	if len(m.lists[m.selectedIdx()].Items()) == 0 && m.focusedIdx != m.selectedIdx() {
+		selectedItem := m.lists[m.focusedIdx].SelectedItem()
+		if selectedItem == nil {
+			return nil, m.extraArgs
+		}
+		it := selectedItem.(item)
+		if it.source == "Presets" {
+			// Run the preset as saved, with its extra arguments
+			s := m.presets[it.title]
+			items, _ = m.resolve(s)
+			return items, mergeArgs(m.extraArgs, s.Args)
+		}
+		if it.node != nil {
+			it = it.node.selectionItem()
+		}
+		return append(items, it), m.extraArgs
+	}
+	for _, li := range m.lists[m.selectedIdx()].Items() {
+		items = append(items, li.(item))
+	}
+	return items, m.selectedExtraArgs()
+}
+
+func (m model) buildArgs(items []item) []string {
+	args, _ := m.configArgs(items, nil)
+	return args
+}
+
+// configArgs builds the arguments for the part of a run listed from a config
+// in monorepo mode, or for the whole run when cfg is nil. It also returns how
+// many locations were passed.
+func (m model) configArgs(items []item, cfg *workspaceConfig) ([]string, int) {
+	args := []string{"test"}
+	if cfg != nil {
+		args = append(args, "--config", filepath.Base(cfg.Config))
+	} else if configPath != "" {
+		args = append(args, "--config", configPath)
+	}
+	args = append(args, m.extraArgs...)
+	args = append(args, m.optionArgs()...)
+
+	seen := map[string]struct{}{}
+	addArg := func(arg string) {
+		if cfg != nil {
+			if m.configOf(arg) != cfg {
+				return
+			}
+			arg = cfg.local(arg)
+		}
+		if _, exists := seen[arg]; !exists {
+			args = append(args, arg)
+			seen[arg] = struct{}{}
+		}
+	}
+
+	grepQueries, grep, grepInvert := m.queryGrep(items)
+	if grepQueries {
+		if grep != "" {
+			args = append(args, "--grep", grep)
+		}
+		if grepInvert != "" {
+			args = append(args, "--grep-invert", grepInvert)
+		}
+	}
+
+	locations, useLocations := m.selectionLocations(items)
+	for _, loc := range locations {
+		addArg(loc)
+	}
+
+	for _, it := range items {
+		if it.stale || it.excluded || it.source == "Projects" {
+			// Stale preset entries are flagged rather than passed to Playwright
+			continue
+		}
+		if useLocations {
+			// Exclusions have already been resolved to file:line locations
+			continue
+		}
+		switch it.source {
+		case "Tags":
+			// Expand tags to their matching tests
+			for _, specItem := range m.tagToSpecs[it.title] {
+				addArg(specItem.description) // file:line
+			}
+		case "Suites":
+			// Expand describe blocks picked from the tree view
+			for _, specItem := range it.specs {
+				addArg(specItem.description)
+			}
+		case "Queries":
+			if !grepQueries {
+				for _, specItem := range it.specs {
+					addArg(specItem.description)
+				}
+			}
+		case "Tests":
+			addArg(it.description) // file:line
+		default:
+			addArg(it.title)
+		}
+	}
+
+	// Selected projects narrow the run; otherwise fall back to the CLI projects
+	for _, p := range m.runProjects(items) {
+		if cfg == nil || cfg.hasProject([]string{p}) {
+			args = append(args, "--project", p)
+		}
+	}
+	return args, len(seen)
+}
+
+// finishRun returns to the picker once an in-app run has exited.
+func (m model) finishRun(err error, report *PlaywrightJSON) (tea.Model, tea.Cmd) {
+	run := m.run
+	run.done = true
+	run.finished = time.Now()
+	run.err = err
+	m.run = nil
+	historyErr := m.recordRun(m.runEntry, err)
+
+	if len(run.tests) == 0 && err != nil {
+		// Playwright never reported any tests, so surface what it printed
+		detail := strings.TrimSpace(run.stderr.String())
+		if lines := strings.Split(detail, "\n"); detail != "" {
+			detail = lines[len(lines)-1]
+		} else {
+			detail = err.Error()
+		}
+		return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle("Run failed: " + detail))
+	}
+
+	if report != nil {
+		results := collectResults(*report)
+		m.recordTimings(results)
+		if len(results) > 0 {
+			m.results = newResultsList(results)
+			m.results.SetSize(m.width, m.height)
+			m.showResults = true
+			if historyErr != nil {
+				return m, m.results.NewStatusMessage(statusRemoveStyle("Error saving run history: " + historyErr.Error()))
+			}
+			return m, nil
+		}
+	}
+
+	counts := run.counts()
+	status := fmt.Sprintf("Run finished: %d passed, %d failed, %d flaky, %d skipped",
+		counts[statusPassed], counts[statusFailed], counts[statusFlaky], counts[statusSkipped])
+	if historyErr != nil {
+		return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle(status + " · error saving run history: " + historyErr.Error()))
+	}
+	if counts[statusFailed] > 0 || err != nil {
+		return m, m.lists[m.focusedIdx].NewStatusMessage(statusRemoveStyle(status))
+	}
+	return m, m.lists[m.focusedIdx].NewStatusMessage(statusSelectStyle(status))
+}
+
+// rerun runs failed tests from the results screen again, with one group of
+// tests per project. Several projects run one after another, each writing to
+// its own output directory so the artifacts of the first are kept.
+func (m model) rerun(groups [][]item) (tea.Model, tea.Cmd) {
+	m.showResults = false
+	if flag, ok := m.execOnlyFlag(); ok {
+		return m, m.lists[m.focusedIdx].NewStatusMessage(inAppRunRefused(flag))
+	}
+	// Re-runs keep the arguments loaded with the selection
+	withArgs := m
+	withArgs.extraArgs = m.selectedExtraArgs()
+	var all []item
+	for _, group := range groups {
+		all = append(all, group...)
+	}
+	entry := withArgs.rerunHistoryEntry(all, nil)
+
+	if len(groups) > 1 {
+		var runs []configRun
+		for _, group := range groups {
+			label := "no project"
+			for _, it := range group {
+				if it.source == "Projects" {
+					label = it.title
+				}
+			}
+			if len(m.workspace) == 0 {
+				runs = append(runs, configRun{Label: label, Args: withArgs.buildArgs(group)})
+				continue
+			}
+			for _, r := range withArgs.workspaceRuns(group) {
+				r.Label = label + " · " + r.Label
+				runs = append(runs, r)
+			}
+		}
+		for i := range runs {
+			if !hasFlag(runs[i].Args, "--output", "-o") {
+				runs[i].Args = append(runs[i].Args, "--output", filepath.Join("test-results", fmt.Sprintf("rerun-%d", i+1)))
+			}
+		}
+		return m.startParts(fmt.Sprintf("Re-running failures in %d projects", len(groups)), runs, entry, true)
+	}
+
+	if len(m.workspace) > 0 {
+		return m.startConfigRuns(withArgs.workspaceRuns(groups[0]), entry)
+	}
+	entry.Args = withArgs.buildArgs(groups[0])
+	m.runEntry = entry
+	return m, startRun(entry.Args, entry.EnvVars)
+}
+
+// updateResults handles keys on the results screen shown after an in-app run.
+func (m model) updateResults(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.results.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.results, cmd = m.results.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		if m.results.FilterState() == list.FilterApplied {
+			break
+		}
+		m.showResults = false
+		return m, nil
+	case "ctrl+c":
+		return m, tea.Quit
+	case "enter":
+		selected := m.results.SelectedItem()
+		if selected == nil {
+			break
+		}
+		r := selected.(resultItem)
+		if !r.failed() {
+			return m, m.results.NewStatusMessage(statusRemoveStyle("Only failed tests can be re-run"))
+		}
+		return m.rerun(rerunGroups([]resultItem{r}))
+	case "f":
+		failed := failedResults(m.results.Items())
+		if len(failed) == 0 {
+			return m, m.results.NewStatusMessage(statusSelectStyle("No failures to re-run"))
+		}
+		return m.rerun(rerunGroups(failed))
+	case "a":
+		failed := failedResults(m.results.Items())
+		if len(failed) == 0 {
+			return m, m.results.NewStatusMessage(statusSelectStyle("No failures to select"))
+		}
+		added := m.selectFailures(failed)
+		m.showResults = false
+		m.focusedIdx = m.selectedIdx()
+		m.rightFocused = true
+		return m, m.lists[m.selectedIdx()].NewStatusMessage(statusSelectStyle(fmt.Sprintf("Selected %d failed test%s", added, plural(added))))
+	}
+
+	var cmd tea.Cmd
+	m.results, cmd = m.results.Update(msg)
+	return m, cmd
+}
+
+// selectFailures moves failed tests into the Selected list, returning how
+// many were added.
+func (m *model) selectFailures(failed []resultItem) int {
+	selected := m.selectedKeys()
+	added := 0
+	for _, it := range rerunItems(failed) {
+		if it.source != "Tests" {
+			continue
+		}
+		// Prefer the listed test so it can be returned to the Tests list later
+		for _, orig := range m.originalTests {
+			if orig.description == it.description {
+				it = orig
+				break
+			}
+		}
+		if _, ok := selected[itemKey(it)]; ok {
+			continue
+		}
+		m.lists[m.selectedIdx()].InsertItem(len(m.lists[m.selectedIdx()].Items()), it)
+		selected[itemKey(it)] = struct{}{}
+		added++
+	}
+	m.refreshLists()
+	return added
+}
+
 func (m model) View() string {
 	if m.quitting {
 		return ""
 	}
+	if m.shards != nil {
+		return appStyle.Render(m.shards.view(m.width, m.height))
+	}
+	if m.run != nil {
+		return appStyle.Render(m.run.view(m.width, m.height))
+	}
+	if m.showResults {
+		return appStyle.Render(m.results.View())
+	}
+	if m.loadErr != nil && m.prompt == promptNone {
+		return appStyle.Render(m.loadErrorView())
+	}
+	if m.loading && m.prompt == promptNone {
+		return appStyle.Render(m.loadingView())
+	}
+	if m.prompt != promptNone && m.showOptions {
+		prompt := m.promptView()
+		return appStyle.Render(lipgloss.JoinVertical(lipgloss.Left, m.optionsView(m.height-lipgloss.Height(prompt)), prompt))
+	}
+	if m.showOptions {
+		return appStyle.Render(m.optionsView(m.height))
+	}
+	if m.prompt != promptNone {
+		prompt := m.promptView()
+		focused := m.lists[m.focusedIdx]
+		focused.SetHeight(max(m.height-lipgloss.Height(prompt), 0))
+		return appStyle.Render(lipgloss.JoinVertical(lipgloss.Left, focused.View(), prompt))
+	}
 	activeTitle := lipgloss.NewStyle().Bold(true).Underline(true).Render()
+	if m.reloading {
+		activeTitle = m.spinner.View() + " Listing tests…"
+	} else if m.lists[m.focusedIdx].Title == "Changed" {
+		activeTitle = m.changesSummary()
+	} else if m.lastRun != nil {
+		activeTitle = "Last run: " + m.lastRun.status()
+		if len(m.timings) == 0 {
+			// Runs with the terminal handed over to Playwright are not timed
+			activeTitle += estimateStyle.Render(" · not timed; runs started with r are")
+		}
+	}
+	if len(m.lists[m.selectedIdx()].Items()) > 0 {
+		if estimate := m.selectionEstimate().String(); estimate != "" {
+			activeTitle = strings.TrimSpace(activeTitle + "  Selected: " + estimate)
+		}
+	}
+	if m.env != "" {
+		activeTitle = strings.TrimSpace(activeTitle + "  Env: " + statusSelectStyle(m.env))
+	}
 
 	left := m.lists[m.focusedIdx]
 	leftView := lipgloss.JoinVertical(lipgloss.Left,
@@ -370,5 +1184,9 @@ func (m model) View() string {
 		left.View(),
 	)
 
-	return lipgloss.JoinHorizontal(lipgloss.Top, leftView)
+	if !m.previewVisible() {
+		return lipgloss.JoinHorizontal(lipgloss.Top, leftView)
+	}
+	preview := m.previewView(m.width-m.listWidth(), m.height)
+	return lipgloss.JoinHorizontal(lipgloss.Top, lipgloss.NewStyle().Width(m.listWidth()).Render(leftView), preview)
 }
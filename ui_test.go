@@ -1,11 +1,22 @@
 package main
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
+// isolateUserDirs points the config and cache directories at temporary ones,
+// so a test neither reads nor writes the developer's history, presets and
+// timings.
+func isolateUserDirs(t *testing.T) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+}
+
 func TestReinsertInOriginalPosition(t *testing.T) {
 	// Define original list of items in correct order
 	original := []item{
@@ -49,3 +60,114 @@ func TestReinsertInOriginalPosition(t *testing.T) {
 		}
 	}
 }
+
+func TestHideSkippedToggle(t *testing.T) {
+	isolateUserDirs(t)
+	pw := PlaywrightJSON{
+		Suites: []Suite{{
+			File: "a.spec.ts",
+			Specs: []Spec{
+				{Title: "runs", File: "a.spec.ts", Line: 1, Tests: []TestInstance{{ProjectName: "chromium"}}},
+				{Title: "skipped", File: "a.spec.ts", Line: 2, Tests: []TestInstance{{ProjectName: "chromium", Annotations: []Annotation{{Type: "skip"}}}}},
+			},
+		}},
+	}
+
+	m := NewModel(pw, nil, nil)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = updated.(model)
+
+	if !m.hideSkipped {
+		t.Fatalf("expected hideSkipped to be enabled")
+	}
+	tests := m.lists[m.listIdx("Tests")].Items()
+	if len(tests) != 1 || tests[0].(item).title != "runs" {
+		t.Errorf("expected only the runnable test to be listed, got %v", tests)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = updated.(model)
+	if len(m.lists[m.listIdx("Tests")].Items()) != 2 {
+		t.Errorf("expected skipped test to be shown again")
+	}
+}
+
+func TestSelect_SameTitledTests(t *testing.T) {
+	isolateUserDirs(t)
+	spec := func(file string) Suite {
+		return Suite{File: file, Specs: []Spec{{Title: "logs in", File: file, Line: 3, Tests: []TestInstance{{ProjectName: "chromium"}}}}}
+	}
+	m := NewModel(PlaywrightJSON{Suites: []Suite{spec("a.spec.ts"), spec("b.spec.ts")}}, nil, nil)
+	m.focusedIdx = m.listIdx("Tests")
+
+	space := tea.KeyMsg{Type: tea.KeySpace}
+	for i := 0; i < 2; i++ {
+		updated, _ := m.Update(space)
+		m = updated.(model)
+	}
+	if got := len(m.lists[m.selectedIdx()].Items()); got != 2 {
+		t.Fatalf("expected both tests to be selected, got %d", got)
+	}
+
+	m.rightFocused = true
+	m.focusedIdx = m.selectedIdx()
+	m.lists[m.focusedIdx].Select(0)
+	updated, _ := m.Update(space)
+	m = updated.(model)
+	if got := len(m.lists[m.selectedIdx()].Items()); got != 1 {
+		t.Errorf("expected only the highlighted test to be removed, got %d left", got)
+	}
+	if got := len(m.lists[m.listIdx("Tests")].Items()); got != 1 {
+		t.Errorf("expected the removed test back on the Tests list, got %d", got)
+	}
+}
+
+func TestBuildArgs(t *testing.T) {
+	m := model{
+		tagToSpecs: map[string][]item{
+			"@smoke": {
+				{source: "Tests", description: "a.spec.ts:3"},
+				{source: "Tests", description: "b.spec.ts:7"},
+			},
+		},
+		projects:  []string{"chromium"},
+		extraArgs: []string{"--headed"},
+	}
+
+	args := m.buildArgs([]item{
+		{source: "Tags", title: "@smoke"},
+		{source: "Tests", description: "a.spec.ts:3"},
+		{source: "Files", title: "c.spec.ts"},
+	})
+	want := []string{"test", "--headed", "a.spec.ts:3", "b.spec.ts:7", "c.spec.ts", "--project", "chromium"}
+	if strings.Join(args, " ") != strings.Join(want, " ") {
+		t.Errorf("unexpected args:\n  got:  %v\n  want: %v", args, want)
+	}
+
+	args = m.buildArgs([]item{{source: "Projects", title: "firefox"}})
+	want = []string{"test", "--headed", "--project", "firefox"}
+	if strings.Join(args, " ") != strings.Join(want, " ") {
+		t.Errorf("selected projects should replace CLI projects:\n  got:  %v\n  want: %v", args, want)
+	}
+}
+
+func TestPrintOnlyEnter(t *testing.T) {
+	isolateUserDirs(t)
+	oldPrint, oldRunner := printOnly, runnerCommand
+	defer func() { printOnly, runnerCommand = oldPrint, oldRunner }()
+	printOnly, runnerCommand = true, "npx playwright"
+
+	m := NewModel(presetTestData(), nil, []string{"--headed"})
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+
+	if want := "npx playwright test --headed cart.spec.ts:3"; m.printCommand != want {
+		t.Errorf("printCommand = %q; want %q", m.printCommand, want)
+	}
+	if cmd == nil {
+		t.Fatalf("expected a quit command")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Errorf("expected the program to quit instead of running Playwright")
+	}
+}
@@ -21,6 +21,28 @@ func plural(n int) string {
 	return "s"
 }
 
+// sourceNoun turns a list name like "Tests" into the noun for one of its items.
+func sourceNoun(source string) string {
+	if source == "Queries" {
+		return "query"
+	}
+	return strings.ToLower(strings.TrimSuffix(source, "s"))
+}
+
+// annotationStyleFor returns the badge style for a skip/fixme/fail/slow annotation.
+func annotationStyleFor(annotation string) lipgloss.Style {
+	color := "8"
+	switch annotation {
+	case "fixme":
+		color = "3"
+	case "fail":
+		color = "5"
+	case "slow":
+		color = "4"
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true)
+}
+
 func tagStyleFor(tag string) lipgloss.Style {
 	hash := sha256.Sum256([]byte(tag))
 	r, g, b := hash[0], hash[1], hash[2]
@@ -49,6 +71,7 @@ func printHelp() {
 
 	fmt.Fprintln(&b, sectionTitle.Render("Usage"))
 	fmt.Fprintln(&b, "  pwgo [options]")
+	fmt.Fprintln(&b, "  pwgo list <tests|files|tags|projects> [--format plain|json|tsv] [options]")
 	fmt.Fprintln(&b)
 
 	fmt.Fprintln(&b, sectionTitle.Render("Common Options"))
@@ -61,7 +84,18 @@ func printHelp() {
 		{"--project <name>...", "Specify project(s) to run tests for"},
 		{"--grep, -g <pattern>", "Only include tests matching this pattern (for --list only)"},
 		{"--grep-invert, -gv <pattern>", "Exclude tests matching this pattern (for --list only)"},
-		{"--config, -c <path>", "Path to Playwright config file"},
+		{"--config, -c <path>", "Path to Playwright config file; repeat to list several"},
+		{"--all-configs", "List every playwright.config.* under the current directory"},
+		{"--preset <name>", "Load a saved preset into the Selected list"},
+		{"--env <name>", "Run tests with a named env set from the config file"},
+		{"--runner <command>", "Command used to invoke Playwright (default: detected from lockfile)"},
+		{"--print, --dry-run", "Print the Playwright command on enter instead of running it"},
+		{"--watch", "Re-list tests when spec files change"},
+		{"--no-watch, --no-stay", "Turn off watch or stay when the config file turns them on"},
+		{"--no-tree, --no-hide-skipped", "Start without the tree view or hidden skipped tests from the config file"},
+		{"--no-grep, --no-grep-invert", "List without the grep patterns from the config file"},
+		{"--no-cache", "List tests with Playwright instead of the saved listing"},
+		{"--stay", "Return to the picker when a run finishes instead of exiting"},
 		{"--json-data-path <path>", "Load Playwright test data from JSON file"},
 		{"--only-changed", "Run only tests related to changed files"},
 		{"--last-failed", "Run only last failed tests"},
@@ -72,11 +106,19 @@ func printHelp() {
 		fmt.Fprintf(&b, "  %-*s %s\n", padding, opt.flag, description.Render(opt.desc))
 	}
 
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, sectionTitle.Render("Config File"))
+	fmt.Fprintf(&b, "%s\n", description.Render(
+		"Defaults are read from the nearest .pwgo.yaml, .pwgo.yml or .pwgo.json\n  above the current directory. Command-line flags take precedence."))
+
 	fmt.Fprintln(&b)
 	fmt.Fprintln(&b, sectionTitle.Render("Examples"))
 	fmt.Fprintln(&b, "  pwgo --project=webkit --only-changed")
 	fmt.Fprintln(&b, "  pwgo --config=playwright.config.ts --last-failed")
 	fmt.Fprintln(&b, "  pwgo --json-data-path=./tests.json --ui")
+	fmt.Fprintln(&b, "  pwgo --runner=\"docker compose exec web npx playwright\"")
+	fmt.Fprintln(&b, "  eval \"$(pwgo --print)\"")
+	fmt.Fprintln(&b, "  pwgo list tags --format json --project chromium")
 
 	fmt.Fprintln(&b)
 	fmt.Fprintln(&b, sectionTitle.Render("Additional Playwright Arguments"))
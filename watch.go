@@ -0,0 +1,340 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	watchDebounce     = 300 * time.Millisecond
+	watchPollInterval = 2 * time.Second
+)
+
+// watchIgnoredDirs are never watched; they are large or written to by runs.
+var watchIgnoredDirs = map[string]struct{}{
+	"node_modules":      {},
+	"test-results":      {},
+	"playwright-report": {},
+	"blob-report":       {},
+}
+
+var watchExtensions = map[string]struct{}{
+	".ts": {}, ".tsx": {}, ".mts": {}, ".cts": {},
+	".js": {}, ".jsx": {}, ".mjs": {}, ".cjs": {},
+}
+
+// specWatcher reports changes to spec files under a directory, using
+// fsnotify where possible and polling otherwise.
+type specWatcher struct {
+	changes chan struct{}
+	done    chan struct{}
+}
+
+type dataChangedMsg struct{}
+
+type dataReloadedMsg struct {
+	data PlaywrightJSON
+	err  error
+}
+
+// watchTarget picks what to watch: the --json-data-path file when data comes
+// from one, otherwise the spec files under the Playwright rootDir.
+func watchTarget(rootDir string) (dir string, match func(path string) bool) {
+	if jsonDataPath != "" {
+		abs, _ := filepath.Abs(jsonDataPath)
+		return filepath.Dir(abs), func(path string) bool { return path == abs }
+	}
+	dir = rootDir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	return dir, specSourceFile
+}
+
+// specSourceFile reports whether path is a JavaScript or TypeScript source
+// that could define or import tests.
+func specSourceFile(path string) bool {
+	_, ok := watchExtensions[filepath.Ext(path)]
+	return ok
+}
+
+func skipWatchDir(path, root string) bool {
+	name := filepath.Base(path)
+	if _, ok := watchIgnoredDirs[name]; ok {
+		return true
+	}
+	return path != root && strings.HasPrefix(name, ".")
+}
+
+func startWatcher(dir string, match func(path string) bool) *specWatcher {
+	w := &specWatcher{changes: make(chan struct{}, 1), done: make(chan struct{})}
+
+	fw, err := fsnotify.NewWatcher()
+	if err == nil {
+		err = addWatchDirs(fw, dir)
+		if err != nil {
+			fw.Close()
+		}
+	}
+	if err != nil {
+		// inotify limits or unsupported filesystems fall back to polling
+		go w.poll(dir, match)
+		return w
+	}
+	go w.watch(fw, dir, match)
+	return w
+}
+
+func addWatchDirs(fw *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if skipWatchDir(path, root) {
+			return filepath.SkipDir
+		}
+		return fw.Add(path)
+	})
+}
+
+func (w *specWatcher) notify() {
+	select {
+	case w.changes <- struct{}{}:
+	default:
+	}
+}
+
+func (w *specWatcher) watch(fw *fsnotify.Watcher, root string, match func(path string) bool) {
+	defer fw.Close()
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-fw.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() && !skipWatchDir(event.Name, root) {
+					addWatchDirs(fw, event.Name)
+				}
+			}
+			if event.Has(fsnotify.Chmod) || !match(event.Name) {
+				continue
+			}
+			debounce = time.After(watchDebounce)
+		case _, ok := <-fw.Errors:
+			if !ok {
+				return
+			}
+		case <-debounce:
+			debounce = nil
+			w.notify()
+		}
+	}
+}
+
+// watchSnapshot records the size and modification time of matching files.
+func watchSnapshot(root string, match func(path string) bool) map[string]string {
+	snapshot := map[string]string{}
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if skipWatchDir(path, root) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !match(path) {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			snapshot[path] = fmt.Sprintf("%d/%d", info.Size(), info.ModTime().UnixNano())
+		}
+		return nil
+	})
+	return snapshot
+}
+
+func (w *specWatcher) poll(root string, match func(path string) bool) {
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+	last := watchSnapshot(root, match)
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			current := watchSnapshot(root, match)
+			if !sameSnapshot(last, current) {
+				w.notify()
+			}
+			last = current
+		}
+	}
+}
+
+func sameSnapshot(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, stamp := range a {
+		if b[path] != stamp {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *specWatcher) stop() {
+	close(w.done)
+}
+
+func waitForChange(changes <-chan struct{}) tea.Cmd {
+	return func() tea.Msg {
+		<-changes
+		return dataChangedMsg{}
+	}
+}
+
+func reloadData() tea.Cmd {
+	return func() tea.Msg {
+		data, err := loadData(currentListOptions)
+		return dataReloadedMsg{data: data, err: err}
+	}
+}
+
+// handleDataChanged starts a reload, or queues one if a reload is running.
+func (m model) handleDataChanged() (tea.Model, tea.Cmd) {
+	return m, tea.Batch(waitForChange(m.watcher.changes), m.startReload())
+}
+
+// handleDataReloaded applies a finished listing. On failure the previous data
+// is kept and the error panel explains what went wrong.
+func (m model) handleDataReloaded(msg dataReloadedMsg) (tea.Model, tea.Cmd) {
+	m.reloading = false
+	m.loadErr = msg.err
+	var cmd tea.Cmd
+	if msg.err == nil {
+		cmd = m.dataLoaded(msg.data)
+	}
+	if m.reloadPending {
+		m.reloadPending = false
+		return m, tea.Batch(cmd, m.startReload())
+	}
+	return m, cmd
+}
+
+func listItems(l list.Model) []item {
+	items := make([]item, len(l.Items()))
+	for i, li := range l.Items() {
+		items[i] = li.(item)
+	}
+	return items
+}
+
+// itemIdentity matches an item across reloads. Unlike itemKey it ignores the
+// line number, which shifts as a spec file is edited.
+func itemIdentity(it item) string {
+	switch it.source {
+	case "Tests", "Suites":
+		return it.source + "|" + it.title + "|" + specFile(it)
+	}
+	return it.source + "|" + it.title
+}
+
+// applyData swaps in freshly listed data, keeping the Selected list and the
+// cursor on each list. It returns how many selected items no longer exist.
+func (m *model) applyData(pwData PlaywrightJSON) (stale int) {
+	cursors := make([]string, len(m.lists))
+	for i := range m.lists {
+		if sel := m.lists[i].SelectedItem(); sel != nil {
+			cursors[i] = itemIdentity(sel.(item))
+		}
+	}
+
+	testList, fileList, tagList, projectList, tagToSpecs, fileToSpecs, _ := buildLists(pwData, m.timings)
+	m.originalTests = listItems(testList)
+	m.originalFiles = listItems(fileList)
+	m.originalTags = listItems(tagList)
+	m.originalProjects = listItems(projectList)
+	m.tagToSpecs = tagToSpecs
+	m.fileToSpecs = fileToSpecs
+	m.tree = buildTree(pwData)
+	attachFileItems(m.tree, m.originalFiles)
+	estimateTree(m.tree, m.timings)
+	m.data = pwData
+	m.rootDir = pwData.Config.RootDir
+	m.workspace = pwData.Workspace
+	m.previewCache = map[string]previewFile{}
+	m.applyChanges()
+
+	var selected []list.Item
+	for _, li := range m.lists[m.selectedIdx()].Items() {
+		it, ok := m.refreshItem(li.(item))
+		if !ok {
+			stale++
+		}
+		selected = append(selected, it)
+	}
+	m.lists[m.selectedIdx()].SetItems(selected)
+	m.refreshLists()
+
+	for i, identity := range cursors {
+		for j, li := range m.lists[i].Items() {
+			if itemIdentity(li.(item)) == identity {
+				m.lists[i].Select(j)
+				break
+			}
+		}
+	}
+	return stale
+}
+
+// refreshItem finds a selected item in the new data, marking it stale when it
+// has gone so it comes back if the test reappears. Exclusions are kept.
+func (m model) refreshItem(it item) (item, bool) {
+	var candidates []item
+	switch it.source {
+	case "Tests":
+		candidates = m.originalTests
+	case "Suites":
+		candidates = m.suiteItems()
+	case "Files":
+		candidates = m.originalFiles
+	case "Tags":
+		candidates = m.originalTags
+	case "Projects":
+		candidates = m.originalProjects
+	case "Queries":
+		expr, err := parseTagQuery(it.title)
+		if err != nil {
+			return it, !it.stale
+		}
+		refreshed := newQueryItem(expr, matchTagQuery(expr, m.originalTests))
+		refreshed.excluded = it.excluded
+		return refreshed, true
+	default:
+		return it, !it.stale
+	}
+
+	for _, c := range candidates {
+		if itemIdentity(c) == itemIdentity(it) {
+			c.excluded = it.excluded
+			return c, true
+		}
+	}
+	it.stale = true
+	return it, false
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestApplyData_PreservesSelectionAndCursor(t *testing.T) {
+	isolateUserDirs(t)
+	m := NewModel(presetTestData(), nil, nil)
+	m.lists[m.selectedIdx()].InsertItem(0, m.originalTests[1]) // Cart › removes
+	m.lists[m.selectedIdx()].InsertItem(1, item{source: "Tags", title: "@smoke", excluded: true})
+	m.refreshLists()
+
+	// Lines shift after an edit and a new test is added above the cursor
+	data := presetTestData()
+	specs := data.Suites[0].Suites[0].Specs
+	specs[0].Line, specs[1].Line = 5, 10
+	data.Suites[0].Suites[0].Specs = append([]Spec{{Title: "new", File: "cart.spec.ts", Line: 3, Tests: []TestInstance{{ProjectName: "chromium"}}}}, specs...)
+	m.lists[m.listIdx("Tests")].Select(0) // Cart › adds
+
+	if stale := m.applyData(data); stale != 0 {
+		t.Errorf("expected no stale items, got %d", stale)
+	}
+	selected := m.lists[m.selectedIdx()].Items()
+	if len(selected) != 2 || selected[0].(item).description != "cart.spec.ts:10" || !selected[1].(item).excluded {
+		t.Errorf("expected the selection to follow the moved test and keep exclusions, got %+v", selected)
+	}
+	tests := m.lists[m.listIdx("Tests")]
+	if len(tests.Items()) != 2 || tests.SelectedItem().(item).title != "Cart › adds" {
+		t.Errorf("expected the cursor to stay on the same test, got %+v", tests.SelectedItem())
+	}
+
+	// Removing the selected test marks it stale, and it recovers when it returns
+	data.Suites[0].Suites[0].Specs = data.Suites[0].Suites[0].Specs[:2]
+	if stale := m.applyData(data); stale != 1 || !m.lists[m.selectedIdx()].Items()[0].(item).stale {
+		t.Errorf("expected the removed test to be stale, got %d", stale)
+	}
+	if stale := m.applyData(presetTestData()); stale != 0 || m.lists[m.selectedIdx()].Items()[0].(item).stale {
+		t.Errorf("expected the test to recover once it is listed again")
+	}
+}
+
+func TestHandleDataReloaded_KeepsDataOnError(t *testing.T) {
+	isolateUserDirs(t)
+	m := NewModel(presetTestData(), nil, nil)
+	m.reloading, m.reloadPending = true, true
+
+	updated, cmd := m.handleDataReloaded(dataReloadedMsg{err: errors.New("SyntaxError\nstack")})
+	m = updated.(model)
+	if len(m.originalTests) != 2 {
+		t.Errorf("expected the previous data to be kept")
+	}
+	if m.loadErr == nil {
+		t.Errorf("expected the error to be kept for the error panel")
+	}
+	if !m.reloading || m.reloadPending || cmd == nil {
+		t.Errorf("expected a queued change to start another reload")
+	}
+}
+
+func TestWatchSnapshot_SkipsIgnoredDirs(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "node_modules", "pkg"), 0o755)
+	os.MkdirAll(filepath.Join(dir, "e2e"), 0o755)
+	os.WriteFile(filepath.Join(dir, "node_modules", "pkg", "index.js"), nil, 0o644)
+	os.WriteFile(filepath.Join(dir, "e2e", "a.spec.ts"), nil, 0o644)
+	os.WriteFile(filepath.Join(dir, "e2e", "notes.md"), nil, 0o644)
+
+	oldPath := jsonDataPath
+	defer func() { jsonDataPath = oldPath }()
+	jsonDataPath = ""
+
+	_, match := watchTarget(dir)
+	snapshot := watchSnapshot(dir, match)
+	if len(snapshot) != 1 {
+		t.Errorf("expected only the spec file, got %v", snapshot)
+	}
+}
+
+func TestStartWatcher_ReportsChanges(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "e2e"), 0o755)
+	oldPath := jsonDataPath
+	defer func() { jsonDataPath = oldPath }()
+	jsonDataPath = ""
+	_, match := watchTarget(dir)
+
+	w := startWatcher(dir, match)
+	defer w.stop()
+
+	os.WriteFile(filepath.Join(dir, "e2e", "a.spec.ts"), []byte("test()"), 0o644)
+	select {
+	case <-w.changes:
+	case <-time.After(watchPollInterval * 2):
+		t.Fatal("expected a change notification")
+	}
+}
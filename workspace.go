@@ -0,0 +1,449 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// workspaceConfigs are the absolute paths of the Playwright configs listed
+// together in monorepo mode. It is empty when a single config is used.
+var workspaceConfigs []string
+
+// workspaceConfig is one config of a monorepo listing.
+type workspaceConfig struct {
+	Config string `json:"config"`
+	// RootDir is where its spec files are relative to the cwd, which
+	// prefixes every file path listed from it
+	RootDir  string   `json:"rootDir"`
+	Projects []string `json:"projects,omitempty"`
+}
+
+// configRun is one Playwright invocation of a run split across configs.
+type configRun struct {
+	Label string   `json:"label"`
+	Dir   string   `json:"dir"`
+	Args  []string `json:"args"`
+}
+
+// selectConfigs picks the configs to list: the --config values, every config
+// under the cwd with --all-configs or the config file's configs patterns.
+// More than one turns on monorepo mode.
+func selectConfigs(flags []string, all bool, cfg pwgoConfig) error {
+	workspaceConfigs = nil
+	var paths []string
+	switch {
+	case len(flags) == 1:
+		configPath = flags[0]
+		return nil
+	case len(flags) > 1:
+		paths = flags
+	case all:
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		paths = discoverConfigs(cwd)
+	case len(cfg.Configs) > 0 && configPath == "":
+		var err error
+		if paths, err = cfg.playwrightConfigs(); err != nil {
+			return err
+		}
+	default:
+		if configPath == "" {
+			configPath = cfg.playwrightConfig()
+		}
+		return nil
+	}
+
+	seen := map[string]bool{}
+	var unique []string
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return err
+		}
+		if !seen[abs] {
+			seen[abs] = true
+			unique = append(unique, abs)
+		}
+	}
+	sort.Strings(unique)
+	switch len(unique) {
+	case 0:
+		return fmt.Errorf("no Playwright configs found")
+	case 1:
+		configPath = unique[0]
+	default:
+		configPath = ""
+		workspaceConfigs = unique
+	}
+	return nil
+}
+
+// isPlaywrightConfig matches playwright.config.ts and its JavaScript variants.
+func isPlaywrightConfig(name string) bool {
+	switch name {
+	case "playwright.config.ts", "playwright.config.mts", "playwright.config.cts",
+		"playwright.config.js", "playwright.config.mjs", "playwright.config.cjs":
+		return true
+	}
+	return false
+}
+
+// discoverConfigs finds every Playwright config under root, skipping the
+// same directories as the watcher.
+func discoverConfigs(root string) []string {
+	var configs []string
+	filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if skipWatchDir(p, root) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isPlaywrightConfig(d.Name()) {
+			configs = append(configs, p)
+		}
+		return nil
+	})
+	return configs
+}
+
+// configLabel names a config by its directory relative to the cwd.
+func configLabel(config string) string {
+	cwd, _ := os.Getwd()
+	dir, err := filepath.Rel(cwd, filepath.Dir(config))
+	if err != nil || dir == "." {
+		return filepath.Base(config)
+	}
+	return filepath.ToSlash(dir)
+}
+
+// listWorkspace lists every config at once, each with Playwright running in
+// the config's directory, and merges the results with spec files made
+// relative to the cwd.
+func listWorkspace(opts listOptions) (PlaywrightJSON, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return PlaywrightJSON{}, err
+	}
+	results := make([]PlaywrightJSON, len(workspaceConfigs))
+	errs := make([]error, len(workspaceConfigs))
+	var wg sync.WaitGroup
+	for i, config := range workspaceConfigs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = listConfig(opts, filepath.Base(config), filepath.Dir(config))
+		}()
+	}
+	wg.Wait()
+
+	merged := PlaywrightJSON{Config: PWConfig{RootDir: cwd}}
+	var failures playwrightErrors
+	for i, config := range workspaceConfigs {
+		label := configLabel(config)
+		var pwErrors playwrightErrors
+		switch {
+		case errors.Is(errs[i], errNoTests):
+			continue
+		case errors.As(errs[i], &pwErrors):
+			for _, e := range pwErrors {
+				e.Message = label + ": " + e.Message
+				failures = append(failures, e)
+			}
+			continue
+		case errs[i] != nil:
+			failures = append(failures, PWError{Message: label + ": " + errs[i].Error()})
+			continue
+		}
+
+		rootDir, err := filepath.Rel(cwd, results[i].Config.RootDir)
+		if err != nil {
+			rootDir = results[i].Config.RootDir
+		}
+		rootDir = filepath.ToSlash(rootDir)
+		merged.Suites = append(merged.Suites, namespaceSuites(results[i].Suites, rootDir)...)
+		merged.Workspace = append(merged.Workspace, workspaceConfig{
+			Config:   config,
+			RootDir:  rootDir,
+			Projects: suiteProjects(results[i].Suites),
+		})
+	}
+	if len(failures) > 0 {
+		return merged, failures
+	}
+	if len(merged.Suites) == 0 {
+		return merged, errNoTests
+	}
+	return merged, nil
+}
+
+// namespaceSuites prefixes the spec files of a listing or report with the
+// directory they are relative to.
+func namespaceSuites(suites []Suite, prefix string) []Suite {
+	if prefix == "" || prefix == "." {
+		return suites
+	}
+	namespaced := make([]Suite, len(suites))
+	for i, s := range suites {
+		if s.Title == s.File && s.Title != "" {
+			s.Title = path.Join(prefix, s.Title)
+		}
+		if s.File != "" {
+			s.File = path.Join(prefix, s.File)
+		}
+		s.Specs = append([]Spec{}, s.Specs...)
+		for j := range s.Specs {
+			if s.Specs[j].File != "" {
+				s.Specs[j].File = path.Join(prefix, s.Specs[j].File)
+			}
+		}
+		s.Suites = namespaceSuites(s.Suites, prefix)
+		namespaced[i] = s
+	}
+	return namespaced
+}
+
+func suiteProjects(suites []Suite) []string {
+	seen := map[string]bool{}
+	var walk func([]Suite)
+	walk = func(suites []Suite) {
+		for _, s := range suites {
+			for _, spec := range s.Specs {
+				for _, t := range spec.Tests {
+					seen[t.ProjectName] = true
+				}
+			}
+			walk(s.Suites)
+		}
+	}
+	walk(suites)
+
+	var projects []string
+	for p := range seen {
+		if p != "" {
+			projects = append(projects, p)
+		}
+	}
+	sort.Strings(projects)
+	return projects
+}
+
+// configOf returns the config a spec location was listed from, going by the
+// longest matching root directory.
+func (m model) configOf(loc string) *workspaceConfig {
+	var owner *workspaceConfig
+	for i := range m.workspace {
+		c := &m.workspace[i]
+		if (c.RootDir == "." || strings.HasPrefix(loc, c.RootDir+"/")) && (owner == nil || len(c.RootDir) > len(owner.RootDir)) {
+			owner = c
+		}
+	}
+	return owner
+}
+
+// local turns a namespaced location back into one relative to the config's
+// root directory.
+func (c workspaceConfig) local(loc string) string {
+	if c.RootDir == "." {
+		return loc
+	}
+	return strings.TrimPrefix(loc, c.RootDir+"/")
+}
+
+func (c workspaceConfig) hasProject(projects []string) bool {
+	for _, p := range projects {
+		for _, own := range c.Projects {
+			if p == own {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// workspaceRuns splits a run into one invocation per config that has any of
+// the selected tests, run from the config's directory.
+func (m model) workspaceRuns(items []item) []configRun {
+	_, total := m.configArgs(items, nil)
+	projects := m.runProjects(items)
+	var runs []configRun
+	for i := range m.workspace {
+		c := &m.workspace[i]
+		args, locations := m.configArgs(items, c)
+		if total > 0 && locations == 0 {
+			continue
+		}
+		if len(projects) > 0 && !c.hasProject(projects) {
+			continue
+		}
+		runs = append(runs, configRun{Label: configLabel(c.Config), Dir: filepath.Dir(c.Config), Args: args})
+	}
+	return runs
+}
+
+// configRuns returns the invocations of a run in monorepo mode, including
+// replays of runs made in it. ok is false for runs of a single config.
+func (m model) configRuns() (runs []configRun, ok bool) {
+	if past, found := m.highlightedHistory(); found {
+		return past.Runs, len(past.Runs) > 0
+	}
+	if len(m.workspace) == 0 {
+		return nil, false
+	}
+	items, extraArgs := m.runItems()
+	if len(items) == 0 || m.leavesNothingToRun(items) {
+		return nil, true
+	}
+	m.extraArgs = extraArgs
+	return m.workspaceRuns(items), true
+}
+
+func configRunCommand(r configRun, env []string) *exec.Cmd {
+	cmd := runCommand(r.Args, env)
+	runIn(cmd, r.Dir)
+	return cmd
+}
+
+// runIn sets the directory a command runs in, keeping a runner given as a
+// relative path pointing at the same file.
+func runIn(cmd *exec.Cmd, dir string) {
+	cmd.Dir = dir
+	if dir != "" && !filepath.IsAbs(cmd.Path) && strings.ContainsRune(cmd.Path, filepath.Separator) {
+		if abs, err := filepath.Abs(cmd.Path); err == nil {
+			cmd.Path = abs
+		}
+	}
+}
+
+// configRunsCommandLine shows each invocation on its own line, run from the
+// config's directory.
+func configRunsCommandLine(runs []configRun, env []string) string {
+	cwd, _ := os.Getwd()
+	lines := make([]string, len(runs))
+	for i, r := range runs {
+		if r.Dir == "" {
+			lines[i] = runCommandLine(r.Args, env)
+			continue
+		}
+		dir, err := filepath.Rel(cwd, r.Dir)
+		if err != nil {
+			dir = r.Dir
+		}
+		lines[i] = fmt.Sprintf("(cd %s && %s)", shellQuote(dir), runCommandLine(r.Args, env))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// execSequence runs several commands one after another with the terminal
+// handed over, returning the first failure.
+type execSequence []*exec.Cmd
+
+func (s execSequence) SetStdin(r io.Reader) {
+	for _, cmd := range s {
+		cmd.Stdin = r
+	}
+}
+
+func (s execSequence) SetStdout(w io.Writer) {
+	for _, cmd := range s {
+		cmd.Stdout = w
+	}
+}
+
+func (s execSequence) SetStderr(w io.Writer) {
+	for _, cmd := range s {
+		cmd.Stderr = w
+	}
+}
+
+func (s execSequence) Run() error {
+	var first error
+	for _, cmd := range s {
+		if err := cmd.Run(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// execConfigRuns runs each config's part of the selection in turn, or prints
+// the commands with --print.
+func (m model) execConfigRuns(runs []configRun) (tea.Model, tea.Cmd) {
+	if len(runs) == 0 {
+		return m, m.nothingToRun()
+	}
+	entry := m.newHistoryEntry(nil)
+	entry.Runs = runs
+	m.quitting = true
+	if printOnly {
+		m.printCommand = configRunsCommandLine(runs, entry.EnvVars)
+		return m, tea.Quit
+	}
+	cmds := make(execSequence, len(runs))
+	for i, r := range runs {
+		cmds[i] = configRunCommand(r, entry.EnvVars)
+	}
+	return m, tea.Exec(cmds, func(err error) tea.Msg {
+		return execFinishedMsg{entry: entry, err: err}
+	})
+}
+
+// startConfigRuns runs each config's part of the selection inside pwgo at
+// the same time, one pane per config.
+func (m model) startConfigRuns(runs []configRun, entry historyEntry) (tea.Model, tea.Cmd) {
+	return m.startParts(fmt.Sprintf("Running %d config%s", len(runs), plural(len(runs))), runs, entry, false)
+}
+
+// startParts runs each invocation inside pwgo with a pane of its own, either
+// all at once or, when sequential, one after another.
+func (m model) startParts(title string, runs []configRun, entry historyEntry, sequential bool) (tea.Model, tea.Cmd) {
+	if len(runs) == 0 {
+		return m, m.nothingToRun()
+	}
+	entry.Runs = runs
+	m.runEntry = entry
+
+	labels := make([]string, len(runs))
+	for i, r := range runs {
+		labels[i] = r.Label
+	}
+	m.shards = newShardedRun(title, labels)
+	cmds := []tea.Cmd{runTick()}
+	for i, r := range runs {
+		m.shards.prefixes[i] = m.reportPrefix(r)
+		start := startPart(i, r.Dir, r.Args, entry.EnvVars)
+		if sequential && i > 0 {
+			m.shards.queued = append(m.shards.queued, start)
+			continue
+		}
+		cmds = append(cmds, start)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// reportPrefix returns the root directory of the config a run was made
+// with, so its results line up with the listed files.
+func (m model) reportPrefix(r configRun) string {
+	for _, c := range m.workspace {
+		if filepath.Dir(c.Config) == r.Dir {
+			return c.RootDir
+		}
+	}
+	return ""
+}
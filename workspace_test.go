@@ -0,0 +1,149 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func withWorkspace(t *testing.T) {
+	t.Helper()
+	oldConfig, oldConfigs, oldRunner := configPath, workspaceConfigs, runnerCommand
+	t.Cleanup(func() { configPath, workspaceConfigs, runnerCommand = oldConfig, oldConfigs, oldRunner })
+}
+
+func TestSelectConfigs(t *testing.T) {
+	withWorkspace(t)
+	dir := t.TempDir()
+	for _, p := range []string{"packages/web/playwright.config.ts", "packages/api/playwright.config.js", "node_modules/x/playwright.config.ts"} {
+		os.MkdirAll(filepath.Join(dir, filepath.Dir(p)), 0o755)
+		os.WriteFile(filepath.Join(dir, p), nil, 0o644)
+	}
+	chdir(t, dir)
+	cwd, _ := os.Getwd()
+
+	if err := selectConfigs(nil, true, pwgoConfig{}); err != nil {
+		t.Fatalf("selectConfigs failed: %v", err)
+	}
+	want := []string{filepath.Join(cwd, "packages/api/playwright.config.js"), filepath.Join(cwd, "packages/web/playwright.config.ts")}
+	if !reflect.DeepEqual(workspaceConfigs, want) || configPath != "" {
+		t.Errorf("expected both packages outside node_modules, got %v", workspaceConfigs)
+	}
+
+	if err := selectConfigs([]string{"packages/web/playwright.config.ts"}, false, pwgoConfig{}); err != nil || workspaceConfigs != nil || configPath != "packages/web/playwright.config.ts" {
+		t.Errorf("expected a single --config to work as before, got %q %v %v", configPath, workspaceConfigs, err)
+	}
+
+	configPath = ""
+	cfg := pwgoConfig{Configs: []string{"packages/*/playwright.config.*"}, path: filepath.Join(cwd, ".pwgo.yaml")}
+	if err := selectConfigs(nil, false, cfg); err != nil || !reflect.DeepEqual(workspaceConfigs, want) {
+		t.Errorf("expected the config file's patterns to be expanded, got %v %v", workspaceConfigs, err)
+	}
+}
+
+func TestNamespaceSuites(t *testing.T) {
+	suites := namespaceSuites(presetTestData().Suites, "packages/web/tests")
+	if suites[0].Title != "packages/web/tests/cart.spec.ts" || suites[0].File != "packages/web/tests/cart.spec.ts" {
+		t.Errorf("expected the file suite to be namespaced, got %+v", suites[0])
+	}
+	inner := suites[0].Suites[0]
+	if inner.Title != "Cart" || inner.Specs[0].File != "packages/web/tests/cart.spec.ts" {
+		t.Errorf("expected describe titles kept and spec files namespaced, got %+v", inner)
+	}
+	if presetTestData().Suites[0].Suites[0].Specs[0].File != "cart.spec.ts" {
+		t.Errorf("expected the original listing to be left alone")
+	}
+}
+
+func TestListWorkspace_MergesConfigs(t *testing.T) {
+	withWorkspace(t)
+	dir := t.TempDir()
+	chdir(t, dir)
+	cwd, _ := os.Getwd()
+	for _, pkg := range []string{"web", "api"} {
+		os.MkdirAll(filepath.Join(cwd, "packages", pkg), 0o755)
+		os.WriteFile(filepath.Join(cwd, "packages", pkg, "playwright.config.ts"), nil, 0o644)
+	}
+	// Lists one spec named after the directory Playwright runs in
+	script := `name=$(basename "$PWD"); echo "{\"config\":{\"rootDir\":\"$PWD/tests\"},\"suites\":[{\"title\":\"$name.spec.ts\",\"file\":\"$name.spec.ts\",\"specs\":[{\"title\":\"works\",\"file\":\"$name.spec.ts\",\"line\":3,\"tests\":[{\"projectName\":\"$name\"}]}]}]}"`
+	os.WriteFile(filepath.Join(cwd, "list.sh"), []byte(script), 0o755)
+	runnerCommand = "sh " + filepath.Join(cwd, "list.sh")
+	workspaceConfigs = []string{filepath.Join(cwd, "packages/api/playwright.config.ts"), filepath.Join(cwd, "packages/web/playwright.config.ts")}
+
+	data, err := listWorkspace(listOptions{})
+	if err != nil {
+		t.Fatalf("listWorkspace failed: %v", err)
+	}
+	var files []string
+	for _, s := range data.Suites {
+		files = append(files, s.File)
+	}
+	if !reflect.DeepEqual(files, []string{"packages/api/tests/api.spec.ts", "packages/web/tests/web.spec.ts"}) {
+		t.Errorf("expected files namespaced by config, got %v", files)
+	}
+	if len(data.Workspace) != 2 || data.Workspace[1].RootDir != "packages/web/tests" || !reflect.DeepEqual(data.Workspace[1].Projects, []string{"web"}) {
+		t.Errorf("unexpected workspace: %+v", data.Workspace)
+	}
+	if data.Config.RootDir != cwd {
+		t.Errorf("expected the merged root to be the cwd, got %s", data.Config.RootDir)
+	}
+}
+
+func workspaceTestData() PlaywrightJSON {
+	web := namespaceSuites(presetTestData().Suites, "packages/web/tests")
+	api := namespaceSuites([]Suite{{
+		Title: "orders.spec.ts",
+		File:  "orders.spec.ts",
+		Specs: []Spec{{Title: "lists", File: "orders.spec.ts", Line: 4, Tags: []string{"@smoke"}, Tests: []TestInstance{{ProjectName: "api"}}}},
+	}}, "packages/api/tests")
+	return PlaywrightJSON{
+		Suites: append(web, api...),
+		Workspace: []workspaceConfig{
+			{Config: "/repo/packages/web/playwright.config.ts", RootDir: "packages/web/tests", Projects: []string{"chromium"}},
+			{Config: "/repo/packages/api/playwright.config.ts", RootDir: "packages/api/tests", Projects: []string{"api"}},
+		},
+	}
+}
+
+func TestWorkspaceRuns_SplitsByConfig(t *testing.T) {
+	withWorkspace(t)
+	configPath = ""
+	isolateUserDirs(t)
+	m := NewModel(workspaceTestData(), nil, nil)
+
+	// A tag spans both configs, with each getting its own locations
+	runs := m.workspaceRuns([]item{{source: "Tags", title: "@smoke"}})
+	if len(runs) != 2 {
+		t.Fatalf("expected a run per config, got %+v", runs)
+	}
+	if runs[0].Dir != "/repo/packages/web" || !reflect.DeepEqual(runs[0].Args, []string{"test", "--config", "playwright.config.ts", "cart.spec.ts:3"}) {
+		t.Errorf("unexpected web run: %+v", runs[0])
+	}
+	if runs[1].Label == "" || !reflect.DeepEqual(runs[1].Args, []string{"test", "--config", "playwright.config.ts", "orders.spec.ts:4"}) {
+		t.Errorf("unexpected api run: %+v", runs[1])
+	}
+
+	// A single file only runs its own config
+	runs = m.workspaceRuns([]item{{source: "Files", title: "packages/api/tests/orders.spec.ts"}})
+	if len(runs) != 1 || runs[0].Dir != "/repo/packages/api" {
+		t.Errorf("expected only the api config, got %+v", runs)
+	}
+
+	// Projects only go to the configs that have them
+	runs = m.workspaceRuns([]item{{source: "Projects", title: "api"}})
+	if len(runs) != 1 || !reflect.DeepEqual(runs[0].Args, []string{"test", "--config", "playwright.config.ts", "--project", "api"}) {
+		t.Errorf("expected only the config with the project, got %+v", runs)
+	}
+
+	// Runs across configs are recorded so they can be replayed
+	m.lists[m.listIdx("Tests")].Select(0)
+	if runs, ok := m.configRuns(); !ok || len(runs) != 1 {
+		t.Fatalf("expected the highlighted test to run in its config, got %+v", runs)
+	}
+	command, _ := m.shownCommandLine()
+	if !strings.HasPrefix(command, "(cd ") || !strings.Contains(command, "cart.spec.ts:3") {
+		t.Errorf("expected the command to change into the config's directory, got %q", command)
+	}
+}